@@ -11,22 +11,73 @@ const (
 	ErrUnsupportedCurrency ErrorCode = "UNSUPPORTED_CURRENCY"
 	ErrDateTooOld          ErrorCode = "DATE_TOO_OLD"
 	ErrFutureDate          ErrorCode = "FUTURE_DATE"
+	ErrFeeExceedsAmount    ErrorCode = "FEE_EXCEEDS_AMOUNT"
+	ErrConflictingParams   ErrorCode = "CONFLICTING_PARAMETERS"
+	ErrInvalidLocale       ErrorCode = "INVALID_LOCALE"
+	ErrInvalidRounding     ErrorCode = "INVALID_ROUNDING"
+	ErrInvalidDateRange    ErrorCode = "INVALID_DATE_RANGE"
+	ErrInvalidRequestBody  ErrorCode = "INVALID_REQUEST_BODY"
+	ErrBatchTooLarge       ErrorCode = "BATCH_TOO_LARGE"
+	ErrInvalidParameter    ErrorCode = "INVALID_PARAMETER"
+	ErrInvalidPair         ErrorCode = "INVALID_PAIR"
+	ErrInvalidThreshold    ErrorCode = "INVALID_THRESHOLD"
+	ErrUnknownAction       ErrorCode = "UNKNOWN_ACTION"
+	ErrTooManySubs         ErrorCode = "TOO_MANY_SUBSCRIPTIONS"
+	ErrInvalidAlertRule    ErrorCode = "INVALID_ALERT_RULE"
+	ErrInvalidAPIKeySpec   ErrorCode = "INVALID_API_KEY_REQUEST"
+
+	ErrUnauthorized  ErrorCode = "UNAUTHORIZED"
+	ErrAPIKeyExpired ErrorCode = "API_KEY_EXPIRED"
+	ErrAPIKeyRevoked ErrorCode = "API_KEY_REVOKED"
+	ErrInvalidToken  ErrorCode = "INVALID_TOKEN"
+
+	ErrInsufficientScope ErrorCode = "INSUFFICIENT_SCOPE"
+
+	ErrAlertNotFound    ErrorCode = "ALERT_NOT_FOUND"
+	ErrUnknownErrorCode ErrorCode = "ERROR_CODE_NOT_FOUND"
+	ErrAPIKeyNotFound   ErrorCode = "API_KEY_NOT_FOUND"
+
+	ErrUnsupportedFormat ErrorCode = "UNSUPPORTED_FORMAT"
+
+	ErrRequestTooLarge ErrorCode = "REQUEST_TOO_LARGE"
+
+	ErrQuotaExceeded  ErrorCode = "QUOTA_EXCEEDED"
+	ErrRateLimited    ErrorCode = "RATE_LIMITED"
+	ErrTooManyFetches ErrorCode = "TOO_MANY_FETCHES"
+
+	ErrRatesUnavailable       ErrorCode = "RATES_UNAVAILABLE"
+	ErrSigningKeysUnavailable ErrorCode = "SIGNING_KEYS_UNAVAILABLE"
+
+	ErrUpstreamTimeout ErrorCode = "UPSTREAM_TIMEOUT"
 
 	ErrAPIFetchFailed ErrorCode = "API_FETCH_FAILED"
 	ErrAPIBadStatus   ErrorCode = "API_BAD_STATUS"
 	ErrAPIBadResponse ErrorCode = "API_BAD_RESPONSE"
 
-	ErrMissingRate      ErrorCode = "MISSING_EXCHANGE_RATE"
-	ErrInvalidRate      ErrorCode = "INVALID_EXCHANGE_RATE"
-	ErrConversionFailed ErrorCode = "CONVERSION_FAILED"
+	ErrMissingRate       ErrorCode = "MISSING_EXCHANGE_RATE"
+	ErrInvalidRate       ErrorCode = "INVALID_EXCHANGE_RATE"
+	ErrConversionFailed  ErrorCode = "CONVERSION_FAILED"
+	ErrInvalidPricing    ErrorCode = "INVALID_PRICING_RULE"
+	ErrCurrencyMismatch  ErrorCode = "CURRENCY_MISMATCH"
+	ErrInvalidAllocation ErrorCode = "INVALID_ALLOCATION"
+	ErrAlertStoreFailed  ErrorCode = "ALERT_STORE_FAILED"
+	ErrAPIKeyStoreFailed ErrorCode = "API_KEY_STORE_FAILED"
 )
 
 type ErrorCategory string
 
 const (
-	CategoryValidation ErrorCategory = "VALIDATION_ERROR"
-	CategoryAPI        ErrorCategory = "API_ERROR"
-	CategoryInternal   ErrorCategory = "INTERNAL_ERROR"
+	CategoryValidation  ErrorCategory = "VALIDATION_ERROR"
+	CategoryAuth        ErrorCategory = "AUTHENTICATION_ERROR"
+	CategoryForbidden   ErrorCategory = "AUTHORIZATION_ERROR"
+	CategoryNotFound    ErrorCategory = "NOT_FOUND_ERROR"
+	CategoryFormat      ErrorCategory = "FORMAT_ERROR"
+	CategoryTooLarge    ErrorCategory = "TOO_LARGE_ERROR"
+	CategoryQuota       ErrorCategory = "QUOTA_ERROR"
+	CategoryAPI         ErrorCategory = "API_ERROR"
+	CategoryUnavailable ErrorCategory = "UNAVAILABLE_ERROR"
+	CategoryTimeout     ErrorCategory = "TIMEOUT_ERROR"
+	CategoryInternal    ErrorCategory = "INTERNAL_ERROR"
 )
 
 type CustomError struct {
@@ -44,16 +95,51 @@ func (e *CustomError) Error() string {
 	return e.Message
 }
 
+// Unwrap returns the underlying error, so errors.Is and errors.As see
+// through a CustomError to its cause.
+func (e *CustomError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is a CustomError with the same code, so
+// errors.Is(err, &CustomError{Code: ErrRatesUnavailable}) matches however
+// deeply err wraps it.
+func (e *CustomError) Is(target error) bool {
+	other, ok := target.(*CustomError)
+	return ok && other.Code == e.Code
+}
+
 func (c CustomError) ErrorMessage() string {
 	return c.Message
 }
 
 func (e *CustomError) GetHTTPStatus() int {
-	switch e.Category {
+	return e.Category.HTTPStatus()
+}
+
+// HTTPStatus returns the status code errors of category are reported with.
+func (category ErrorCategory) HTTPStatus() int {
+	switch category {
 	case CategoryValidation:
 		return 400
+	case CategoryAuth:
+		return 401
+	case CategoryForbidden:
+		return 403
+	case CategoryNotFound:
+		return 404
+	case CategoryFormat:
+		return 406
+	case CategoryTooLarge:
+		return 413
+	case CategoryQuota:
+		return 429
 	case CategoryAPI:
 		return 502
+	case CategoryUnavailable:
+		return 503
+	case CategoryTimeout:
+		return 504
 	case CategoryInternal:
 		return 500
 	default:
@@ -61,6 +147,75 @@ func (e *CustomError) GetHTTPStatus() int {
 	}
 }
 
+// CodeInfo documents an ErrorCode for the API description.
+type CodeInfo struct {
+	Code        ErrorCode
+	Category    ErrorCategory
+	Description string
+}
+
+// Codes lists every ErrorCode the service can return.
+var Codes = []CodeInfo{
+	{ErrMissingParameter, CategoryValidation, "A required parameter is missing"},
+	{ErrInvalidAmount, CategoryValidation, "The amount is not a positive decimal number"},
+	{ErrInvalidDate, CategoryValidation, "A date is not in YYYY-MM-DD format"},
+	{ErrUnsupportedCurrency, CategoryValidation, "A currency is not configured or not quoted by the provider"},
+	{ErrDateTooOld, CategoryValidation, "A date is more than 90 days in the past"},
+	{ErrFutureDate, CategoryValidation, "A date is in the future"},
+	{ErrFeeExceedsAmount, CategoryValidation, "The quote fee is larger than the amount"},
+	{ErrConflictingParams, CategoryValidation, "Two mutually exclusive parameters were both given"},
+	{ErrInvalidLocale, CategoryValidation, "The locale is not a valid BCP 47 tag"},
+	{ErrInvalidRounding, CategoryValidation, "The rounding mode or precision is invalid"},
+	{ErrInvalidDateRange, CategoryValidation, "start_date is after end_date"},
+	{ErrInvalidRequestBody, CategoryValidation, "The request body does not match the expected JSON"},
+	{ErrBatchTooLarge, CategoryValidation, "A batch is empty or has too many items"},
+	{ErrTooManyFetches, CategoryValidation, "The request needs more uncached days than the client's upstream rate limit allows in one request"},
+	{ErrInvalidParameter, CategoryValidation, "A parameter does not match its documented schema"},
+	{ErrInvalidPair, CategoryValidation, "A currency pair is not in FROM/TO format"},
+	{ErrInvalidThreshold, CategoryValidation, "A subscription threshold is not a non-negative percentage"},
+	{ErrUnknownAction, CategoryValidation, "A WebSocket message has an unknown action"},
+	{ErrTooManySubs, CategoryValidation, "A WebSocket connection has too many subscriptions"},
+	{ErrInvalidAlertRule, CategoryValidation, "An alert rule has an invalid condition, threshold, cooldown or webhook URL"},
+	{ErrInvalidAPIKeySpec, CategoryValidation, "An API key request has an unknown scope, a negative quota or an expiry in the past"},
+	{ErrUnauthorized, CategoryAuth, "Credentials are missing or invalid"},
+	{ErrAPIKeyExpired, CategoryAuth, "The API key has expired"},
+	{ErrAPIKeyRevoked, CategoryAuth, "The API key has been revoked"},
+	{ErrInvalidToken, CategoryAuth, "The bearer token is malformed, unsigned by a trusted key, expired, or for another issuer or audience"},
+	{ErrInsufficientScope, CategoryForbidden, "The credentials do not grant the scope the endpoint requires"},
+	{ErrAlertNotFound, CategoryNotFound, "No alert rule has the given ID"},
+	{ErrUnknownErrorCode, CategoryNotFound, "No error code has the given name"},
+	{ErrAPIKeyNotFound, CategoryNotFound, "No API key has the given ID"},
+	{ErrUnsupportedFormat, CategoryFormat, "The endpoint cannot respond in the requested format"},
+	{ErrRequestTooLarge, CategoryTooLarge, "The request body is larger than the service accepts"},
+	{ErrQuotaExceeded, CategoryQuota, "The client has used up its request quota"},
+	{ErrRateLimited, CategoryQuota, "The client is sending requests faster than its rate limit"},
+	{ErrAPIFetchFailed, CategoryAPI, "The rate provider could not be reached"},
+	{ErrAPIBadStatus, CategoryAPI, "The rate provider returned an error status"},
+	{ErrAPIBadResponse, CategoryAPI, "The rate provider returned an unreadable response"},
+	{ErrRatesUnavailable, CategoryUnavailable, "No rates are cached and the rate provider could not supply them"},
+	{ErrSigningKeysUnavailable, CategoryUnavailable, "The keys to verify bearer tokens could not be loaded from the JWKS"},
+	{ErrUpstreamTimeout, CategoryTimeout, "The rate provider did not respond in time"},
+	{ErrMissingRate, CategoryInternal, "No rate is available for a currency"},
+	{ErrInvalidRate, CategoryInternal, "The provider quoted a zero rate"},
+	{ErrConversionFailed, CategoryInternal, "The conversion could not be computed"},
+	{ErrInvalidPricing, CategoryInternal, "A pricing rule is misconfigured"},
+	{ErrCurrencyMismatch, CategoryInternal, "Amounts in different currencies were combined"},
+	{ErrInvalidAllocation, CategoryInternal, "An amount could not be allocated"},
+	{ErrAlertStoreFailed, CategoryInternal, "Alert rules could not be saved"},
+	{ErrAPIKeyStoreFailed, CategoryInternal, "API keys could not be saved"},
+}
+
+// Lookup returns the documentation of code.
+func Lookup(code ErrorCode) (CodeInfo, bool) {
+	for _, info := range Codes {
+		if info.Code == code {
+			return info, true
+		}
+	}
+
+	return CodeInfo{}, false
+}
+
 func newCustomError(code ErrorCode, category ErrorCategory, message string, err error) *CustomError {
 	return &CustomError{
 		Code:     code,
@@ -133,6 +288,261 @@ func FutureDateError() *CustomError {
 	)
 }
 
+func FeeExceedsAmountError() *CustomError {
+	return newCustomError(
+		ErrFeeExceedsAmount,
+		CategoryValidation,
+		"amount does not cover the conversion fee",
+		nil,
+	)
+}
+
+func ConflictingParametersError(first, second string) *CustomError {
+	return newCustomError(
+		ErrConflictingParams,
+		CategoryValidation,
+		fmt.Sprintf("parameters %s and %s cannot be used together", first, second),
+		nil,
+	)
+}
+
+func InvalidLocaleError(locale string) *CustomError {
+	return newCustomError(
+		ErrInvalidLocale,
+		CategoryValidation,
+		fmt.Sprintf("invalid locale: %s", locale),
+		nil,
+	)
+}
+
+func InvalidRoundingError(message string) *CustomError {
+	return newCustomError(
+		ErrInvalidRounding,
+		CategoryValidation,
+		fmt.Sprintf("invalid rounding: %s", message),
+		nil,
+	)
+}
+
+func InvalidDateRangeError() *CustomError {
+	return newCustomError(
+		ErrInvalidDateRange,
+		CategoryValidation,
+		"start_date must not be after end_date",
+		nil,
+	)
+}
+
+func InvalidRequestBodyError(err error) *CustomError {
+	return newCustomError(
+		ErrInvalidRequestBody,
+		CategoryValidation,
+		"invalid request body",
+		err,
+	)
+}
+
+func BatchTooLargeError(limit int) *CustomError {
+	return newCustomError(
+		ErrBatchTooLarge,
+		CategoryValidation,
+		fmt.Sprintf("batch must contain between 1 and %d items", limit),
+		nil,
+	)
+}
+
+func TooManyFetchesError(fetches, burst int) *CustomError {
+	return newCustomError(
+		ErrTooManyFetches,
+		CategoryValidation,
+		fmt.Sprintf("request needs %d uncached days, more than the maximum of %d per request; split it into smaller ranges", fetches, burst),
+		nil,
+	)
+}
+
+func InvalidParameterError(param, reason string) *CustomError {
+	return newCustomError(
+		ErrInvalidParameter,
+		CategoryValidation,
+		fmt.Sprintf("invalid parameter %s: %s", param, reason),
+		nil,
+	)
+}
+
+func InvalidPairError(pair string) *CustomError {
+	return newCustomError(
+		ErrInvalidPair,
+		CategoryValidation,
+		fmt.Sprintf("invalid currency pair %q, use FROM/TO", pair),
+		nil,
+	)
+}
+
+func InvalidThresholdError() *CustomError {
+	return newCustomError(
+		ErrInvalidThreshold,
+		CategoryValidation,
+		"threshold must be a non-negative percentage",
+		nil,
+	)
+}
+
+func UnknownActionError(action string) *CustomError {
+	return newCustomError(
+		ErrUnknownAction,
+		CategoryValidation,
+		fmt.Sprintf("unknown action: %s", action),
+		nil,
+	)
+}
+
+func TooManySubscriptionsError(limit int) *CustomError {
+	return newCustomError(
+		ErrTooManySubs,
+		CategoryValidation,
+		fmt.Sprintf("at most %d subscriptions per connection", limit),
+		nil,
+	)
+}
+
+func InvalidAlertRuleError(reason string) *CustomError {
+	return newCustomError(
+		ErrInvalidAlertRule,
+		CategoryValidation,
+		fmt.Sprintf("invalid alert rule: %s", reason),
+		nil,
+	)
+}
+
+func InvalidAPIKeySpecError(reason string) *CustomError {
+	return newCustomError(
+		ErrInvalidAPIKeySpec,
+		CategoryValidation,
+		fmt.Sprintf("invalid API key request: %s", reason),
+		nil,
+	)
+}
+
+//authentication errors
+
+func UnauthorizedError() *CustomError {
+	return newCustomError(
+		ErrUnauthorized,
+		CategoryAuth,
+		"missing or invalid credentials",
+		nil,
+	)
+}
+
+func APIKeyExpiredError() *CustomError {
+	return newCustomError(
+		ErrAPIKeyExpired,
+		CategoryAuth,
+		"API key has expired",
+		nil,
+	)
+}
+
+func APIKeyRevokedError() *CustomError {
+	return newCustomError(
+		ErrAPIKeyRevoked,
+		CategoryAuth,
+		"API key has been revoked",
+		nil,
+	)
+}
+
+func InvalidTokenError(err error) *CustomError {
+	return newCustomError(
+		ErrInvalidToken,
+		CategoryAuth,
+		fmt.Sprintf("invalid bearer token: %v", err),
+		err,
+	)
+}
+
+//authorization errors
+
+func InsufficientScopeError(scope string) *CustomError {
+	return newCustomError(
+		ErrInsufficientScope,
+		CategoryForbidden,
+		fmt.Sprintf("credentials do not grant the %s scope", scope),
+		nil,
+	)
+}
+
+//not found errors
+
+func AlertNotFoundError(id string) *CustomError {
+	return newCustomError(
+		ErrAlertNotFound,
+		CategoryNotFound,
+		fmt.Sprintf("alert rule not found: %s", id),
+		nil,
+	)
+}
+
+func UnknownErrorCodeError(code string) *CustomError {
+	return newCustomError(
+		ErrUnknownErrorCode,
+		CategoryNotFound,
+		fmt.Sprintf("error code not found: %s", code),
+		nil,
+	)
+}
+
+func APIKeyNotFoundError(id string) *CustomError {
+	return newCustomError(
+		ErrAPIKeyNotFound,
+		CategoryNotFound,
+		fmt.Sprintf("API key not found: %s", id),
+		nil,
+	)
+}
+
+//format errors
+
+func UnsupportedFormatError(format string) *CustomError {
+	return newCustomError(
+		ErrUnsupportedFormat,
+		CategoryFormat,
+		fmt.Sprintf("response not available as %s", format),
+		nil,
+	)
+}
+
+//size errors
+
+func RequestTooLargeError(limit int64) *CustomError {
+	return newCustomError(
+		ErrRequestTooLarge,
+		CategoryTooLarge,
+		fmt.Sprintf("request body must not exceed %d bytes", limit),
+		nil,
+	)
+}
+
+//quota errors
+
+func QuotaExceededError(message string) *CustomError {
+	return newCustomError(
+		ErrQuotaExceeded,
+		CategoryQuota,
+		fmt.Sprintf("quota exceeded: %s", message),
+		nil,
+	)
+}
+
+func RateLimitedError(bucket string) *CustomError {
+	return newCustomError(
+		ErrRateLimited,
+		CategoryQuota,
+		fmt.Sprintf("rate limit exceeded for %s requests", bucket),
+		nil,
+	)
+}
+
 //api errors
 
 func APIFetchError(err error) *CustomError {
@@ -162,6 +572,35 @@ func APIResponseError(err error) *CustomError {
 	)
 }
 
+//availability errors
+
+func RatesUnavailableError(err error) *CustomError {
+	return newCustomError(
+		ErrRatesUnavailable,
+		CategoryUnavailable,
+		"exchange rates are temporarily unavailable",
+		err,
+	)
+}
+
+func SigningKeysUnavailableError(err error) *CustomError {
+	return newCustomError(
+		ErrSigningKeysUnavailable,
+		CategoryUnavailable,
+		"token signing keys are temporarily unavailable",
+		err,
+	)
+}
+
+func UpstreamTimeoutError(err error) *CustomError {
+	return newCustomError(
+		ErrUpstreamTimeout,
+		CategoryTimeout,
+		"timed out waiting for the rate provider",
+		err,
+	)
+}
+
 //internal service error
 
 func MissingRateError(currency string) *CustomError {
@@ -190,3 +629,48 @@ func ConversionError(err error) *CustomError {
 		err,
 	)
 }
+
+func InvalidPricingError(message string) *CustomError {
+	return newCustomError(
+		ErrInvalidPricing,
+		CategoryInternal,
+		fmt.Sprintf("invalid pricing rule: %s", message),
+		nil,
+	)
+}
+
+func CurrencyMismatchError(first, second string) *CustomError {
+	return newCustomError(
+		ErrCurrencyMismatch,
+		CategoryInternal,
+		fmt.Sprintf("cannot combine amounts in %s and %s", first, second),
+		nil,
+	)
+}
+
+func InvalidAllocationError(message string) *CustomError {
+	return newCustomError(
+		ErrInvalidAllocation,
+		CategoryInternal,
+		fmt.Sprintf("invalid allocation: %s", message),
+		nil,
+	)
+}
+
+func AlertStoreError(err error) *CustomError {
+	return newCustomError(
+		ErrAlertStoreFailed,
+		CategoryInternal,
+		"failed to save alert rules",
+		err,
+	)
+}
+
+func APIKeyStoreError(err error) *CustomError {
+	return newCustomError(
+		ErrAPIKeyStoreFailed,
+		CategoryInternal,
+		"failed to save API keys",
+		err,
+	)
+}
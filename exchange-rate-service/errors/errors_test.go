@@ -0,0 +1,40 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestCustomErrorUnwrap(t *testing.T) {
+	err := fmt.Errorf("loading rates: %w", RatesUnavailableError(APIFetchError(io.ErrUnexpectedEOF)))
+
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Error("Expected the cause to be found through both CustomErrors")
+	}
+	if !errors.Is(err, &CustomError{Code: ErrAPIFetchFailed}) {
+		t.Error("Expected the wrapped API_FETCH_FAILED to match by code")
+	}
+	if errors.Is(err, &CustomError{Code: ErrUpstreamTimeout}) {
+		t.Error("Unexpected match for UPSTREAM_TIMEOUT")
+	}
+
+	var customErr *CustomError
+	if !errors.As(err, &customErr) || customErr.Code != ErrRatesUnavailable {
+		t.Errorf("errors.As found %v, expected RATES_UNAVAILABLE", customErr)
+	}
+}
+
+func TestCodesAreDocumented(t *testing.T) {
+	for _, info := range Codes {
+		found, ok := Lookup(info.Code)
+		if !ok || found != info {
+			t.Errorf("Lookup(%s) = %v, %v", info.Code, found, ok)
+		}
+	}
+
+	if _, ok := Lookup("NOPE"); ok {
+		t.Error("Expected unknown code to be missing")
+	}
+}
@@ -0,0 +1,238 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+	pb "github.com/yourusername/exchange-rate-service/proto/exchangerate/v1"
+	"github.com/yourusername/exchange-rate-service/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+const (
+	apiKeyMetadata        = "x-api-key"
+	authorizationMetadata = "authorization"
+	bearerPrefix          = "Bearer "
+)
+
+// methodScopes gives the scope each exchange rate RPC requires, the same as
+// its HTTP route. Other methods, such as the health service, are open.
+var methodScopes = map[string]service.Scope{
+	pb.ExchangeRateService_Convert_FullMethodName:      service.ScopeConvert,
+	pb.ExchangeRateService_BatchConvert_FullMethodName: service.ScopeConvert,
+	pb.ExchangeRateService_GetRates_FullMethodName:     service.ScopeRates,
+	pb.ExchangeRateService_WatchRates_FullMethodName:   service.ScopeRates,
+}
+
+// guard authenticates and rate limits calls like the HTTP Authenticator and
+// Limiter. Calls carry an API key in x-api-key metadata or a bearer token in
+// authorization metadata, and count against the key's daily quota once they
+// pass rate limiting. Failed attempts are limited per peer address.
+type guard struct {
+	keys        *service.APIKeyService
+	tokens      *service.TokenVerifier
+	limiter     *service.RateLimiter
+	rateFetcher *service.RateFetcherService
+}
+
+func (g *guard) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := g.check(ctx, info.FullMethod, req); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// stream checks streams once, when they open. WatchRates only sends the
+// latest rates, so it is charged to the cached bucket.
+func (g *guard) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := g.check(ss.Context(), info.FullMethod, nil); err != nil {
+		return err
+	}
+
+	return handler(srv, ss)
+}
+
+// check returns the status a call to method with req is rejected with, or
+// nil to let it through.
+func (g *guard) check(ctx context.Context, method string, req any) error {
+	scope, ok := methodScopes[method]
+	if !ok {
+		return nil
+	}
+
+	address := "ip:" + peerHost(ctx)
+	if err := g.allowAttempt(ctx, address); err != nil {
+		return err
+	}
+
+	client, key, err := g.authenticate(ctx, scope)
+	if err != nil {
+		var customErr *appErrors.CustomError
+		if g.limiter != nil && errors.As(err, &customErr) && customErr.Category == appErrors.CategoryAuth {
+			if err := g.limiter.AuthFailed(ctx, address); err != nil {
+				log.Printf("Failed to record auth failure: %v", err)
+			}
+		}
+		return toStatus(err)
+	}
+
+	if err := g.limit(ctx, client, req); err != nil {
+		return err
+	}
+
+	if key != nil {
+		if _, err := g.keys.ChargeUsage(*key); err != nil {
+			return toStatus(err)
+		}
+	}
+
+	return nil
+}
+
+// authenticate checks the call's credentials grant scope and returns the
+// client they identify, named as the HTTP Limiter names it, and the API key
+// if they are one.
+func (g *guard) authenticate(ctx context.Context, scope service.Scope) (string, *service.APIKey, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if header := firstValue(md, authorizationMetadata); len(header) >= len(bearerPrefix) && strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+		if g.tokens == nil {
+			return "", nil, appErrors.UnauthorizedError()
+		}
+
+		principal, err := g.tokens.Verify(ctx, strings.TrimSpace(header[len(bearerPrefix):]), scope)
+		if err != nil {
+			return "", nil, err
+		}
+		return "sub:" + principal.Subject, nil, nil
+	}
+
+	secret := firstValue(md, apiKeyMetadata)
+	if secret == "" {
+		return "", nil, appErrors.UnauthorizedError()
+	}
+
+	key, err := g.keys.Authenticate(secret, scope)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return "key:" + key.ID, &key, nil
+}
+
+// allowAttempt rejects calls from an address that has failed to
+// authenticate too often, with a retry-after header. When the store fails
+// the call is let through.
+func (g *guard) allowAttempt(ctx context.Context, address string) error {
+	if g.limiter == nil {
+		return nil
+	}
+
+	result, err := g.limiter.AllowAuthAttempt(ctx, address)
+	if err != nil {
+		log.Printf("Auth failure limit check failed, allowing call: %v", err)
+		return nil
+	}
+	if result == nil || result.Allowed {
+		return nil
+	}
+
+	retryAfter := max(1, int(math.Ceil(result.RetryAfter.Seconds())))
+	grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(retryAfter)))
+	return toStatus(appErrors.RateLimitedError(result.Bucket))
+}
+
+// limit charges the call to client's bucket. Denied calls get a
+// retry-after header with the seconds to wait; calls needing more upstream
+// tokens than the bucket holds are invalid arguments instead. When the
+// store fails the call is let through.
+func (g *guard) limit(ctx context.Context, client string, req any) error {
+	if g.limiter == nil {
+		return nil
+	}
+
+	fetches := 0
+	for _, date := range requestDates(req) {
+		fetches += g.rateFetcher.UncachedDays(date, date)
+	}
+
+	result, err := g.limiter.Allow(ctx, client, fetches)
+	if errors.Is(err, &appErrors.CustomError{Code: appErrors.ErrTooManyFetches}) {
+		return toStatus(err)
+	}
+	if err != nil {
+		log.Printf("Rate limit check failed, allowing call: %v", err)
+		return nil
+	}
+
+	if result == nil || result.Allowed {
+		return nil
+	}
+
+	retryAfter := max(1, int(math.Ceil(result.RetryAfter.Seconds())))
+	grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(retryAfter)))
+	return toStatus(appErrors.RateLimitedError(result.Bucket))
+}
+
+// requestDates returns the distinct dates req needs rates for. Invalid dates
+// are left to the method to reject.
+func requestDates(req any) []time.Time {
+	var values []string
+	switch req := req.(type) {
+	case *pb.ConvertRequest:
+		values = append(values, req.Date)
+	case *pb.BatchConvertRequest:
+		for _, item := range req.Items {
+			values = append(values, item.Date)
+		}
+	case *pb.GetRatesRequest:
+		values = append(values, req.Date)
+	}
+
+	seen := make(map[string]bool)
+	var dates []time.Time
+	for _, value := range values {
+		date, err := parseDate(value)
+		if err != nil || date == nil || seen[value] {
+			continue
+		}
+		seen[value] = true
+		dates = append(dates, *date)
+	}
+
+	return dates
+}
+
+// peerHost returns the host of the call's peer address, or the whole
+// address when it has no port.
+func peerHost(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
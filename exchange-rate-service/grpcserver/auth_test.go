@@ -0,0 +1,90 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	pb "github.com/yourusername/exchange-rate-service/proto/exchangerate/v1"
+	"github.com/yourusername/exchange-rate-service/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestGuardAuthenticatesAndLimits(t *testing.T) {
+	t.Setenv("API_KEY", "test")
+	t.Setenv("API_KEYS_FILE", "")
+	t.Setenv("API_ADMIN_KEY", "")
+	t.Setenv("RATE_LIMIT_CACHED", "2/1m")
+	t.Setenv("RATE_LIMIT_AUTH_FAILURES", "4/1m")
+	t.Setenv("RATE_LIMIT_REDIS_URL", "")
+
+	keys := service.NewAPIKeyService()
+	_, ratesKey, err := keys.IssueKey(service.APIKeySpec{Name: "rates", Scopes: []string{"rates"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	_, convertKey, err := keys.IssueKey(service.APIKeySpec{Name: "convert", Scopes: []string{"convert"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	listener := bufconn.Listen(1 << 20)
+	server := NewGRPCServer(ctx, service.NewRateFetcherService(), keys, nil, service.NewRateLimiter())
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer conn.Close()
+
+	client := pb.NewExchangeRateServiceClient(conn)
+	// A request missing its target currency reaches the method only once
+	// the guard lets it through.
+	invalid := &pb.ConvertRequest{From: "USD", Amount: "10"}
+
+	tests := []struct {
+		name string
+		md   metadata.MD
+		code codes.Code
+	}{
+		{"no credentials", nil, codes.Unauthenticated},
+		{"unknown key", metadata.Pairs("x-api-key", "erk_wrong"), codes.Unauthenticated},
+		{"bearer token without OIDC", metadata.Pairs("authorization", "Bearer token"), codes.Unauthenticated},
+		{"missing scope", metadata.Pairs("x-api-key", ratesKey), codes.PermissionDenied},
+		{"allowed", metadata.Pairs("x-api-key", convertKey), codes.InvalidArgument},
+		{"allowed again", metadata.Pairs("x-api-key", convertKey), codes.InvalidArgument},
+		{"rate limited", metadata.Pairs("x-api-key", convertKey), codes.ResourceExhausted},
+		{"unknown key again", metadata.Pairs("x-api-key", "erk_wrong"), codes.Unauthenticated},
+		// Four failures use up the peer's auth bucket, even for a valid key.
+		{"too many failed attempts", metadata.Pairs("x-api-key", convertKey), codes.ResourceExhausted},
+	}
+
+	for _, tt := range tests {
+		var header metadata.MD
+		_, err := client.Convert(metadata.NewOutgoingContext(context.Background(), tt.md), invalid, grpc.Header(&header))
+		if status.Code(err) != tt.code {
+			t.Errorf("%s: %v, expected %s", tt.name, err, tt.code)
+		}
+		if tt.code == codes.ResourceExhausted && len(header.Get("retry-after")) == 0 {
+			t.Errorf("%s: expected a retry-after header, got %v", tt.name, header)
+		}
+	}
+
+	if _, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Errorf("Expected the health service to be open, got %v", err)
+	}
+}
@@ -0,0 +1,302 @@
+// Package grpcserver serves the exchange rate API over gRPC, backed by the
+// same RateFetcherService as the HTTP handlers.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+	"github.com/yourusername/exchange-rate-service/money"
+	pb "github.com/yourusername/exchange-rate-service/proto/exchangerate/v1"
+	"github.com/yourusername/exchange-rate-service/protoconv"
+	"github.com/yourusername/exchange-rate-service/service"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultBase = "USD"
+
+	// errorDomain identifies the service in google.rpc.ErrorInfo details.
+	errorDomain = "exchange-rate-service"
+
+	healthInterval = 30 * time.Second
+)
+
+// Server implements pb.ExchangeRateServiceServer.
+type Server struct {
+	pb.UnimplementedExchangeRateServiceServer
+
+	rateFetcher *service.RateFetcherService
+}
+
+func NewServer(rateFetcher *service.RateFetcherService) *Server {
+	return &Server{
+		rateFetcher: rateFetcher,
+	}
+}
+
+// NewGRPCServer returns a gRPC server with the exchange rate service and the
+// standard health service registered. Exchange rate calls need an API key
+// checked by keys, or a bearer token checked by tokens, with the scope of
+// the matching HTTP route, and are rate limited by limiter; a nil tokens
+// rejects bearer tokens and a nil limiter leaves calls unlimited. Health
+// follows the service's readiness until ctx is done.
+func NewGRPCServer(ctx context.Context, rateFetcher *service.RateFetcherService, keys *service.APIKeyService, tokens *service.TokenVerifier, limiter *service.RateLimiter) *grpc.Server {
+	guard := &guard{
+		keys:        keys,
+		tokens:      tokens,
+		limiter:     limiter,
+		rateFetcher: rateFetcher,
+	}
+
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(guard.unary),
+		grpc.StreamInterceptor(guard.stream),
+	)
+	pb.RegisterExchangeRateServiceServer(server, NewServer(rateFetcher))
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	go watchHealth(ctx, rateFetcher, healthServer)
+
+	return server
+}
+
+// watchHealth reports SERVING for the overall server and the exchange rate
+// service while the rate fetcher is ready.
+func watchHealth(ctx context.Context, rateFetcher *service.RateFetcherService, healthServer *health.Server) {
+	ticker := time.NewTicker(healthInterval)
+	defer ticker.Stop()
+
+	for {
+		status := healthpb.HealthCheckResponse_NOT_SERVING
+		if rateFetcher.Health().Ready {
+			status = healthpb.HealthCheckResponse_SERVING
+		}
+		healthServer.SetServingStatus("", status)
+		healthServer.SetServingStatus(pb.ExchangeRateService_ServiceDesc.ServiceName, status)
+
+		select {
+		case <-ctx.Done():
+			healthServer.Shutdown()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Server) Convert(ctx context.Context, req *pb.ConvertRequest) (*pb.Conversion, error) {
+	request, err := conversionRequest(req)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	var conversion *service.Conversion
+	if request.Reverse {
+		conversion, err = s.rateFetcher.ConvertCurrencyReverse(request.To, request.Amount, request.Date)
+	} else {
+		conversion, err = s.rateFetcher.ConvertCurrency(request.Amount, request.To, request.Date, request.Rounding)
+	}
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return protoconv.Conversion(conversion), nil
+}
+
+func (s *Server) BatchConvert(ctx context.Context, req *pb.BatchConvertRequest) (*pb.BatchConvertResponse, error) {
+	if len(req.Items) == 0 || len(req.Items) > service.MaxBatchSize {
+		return nil, toStatus(appErrors.BatchTooLargeError(service.MaxBatchSize))
+	}
+
+	errs := make([]error, len(req.Items))
+	conversions := make([]*service.Conversion, len(req.Items))
+	var requests []service.ConversionRequest
+	var indexes []int
+	for i, item := range req.Items {
+		request, err := conversionRequest(item)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		requests = append(requests, request)
+		indexes = append(indexes, i)
+	}
+
+	for j, result := range s.rateFetcher.ConvertBatch(requests, money.Rounding{}) {
+		conversions[indexes[j]], errs[indexes[j]] = result.Conversion, result.Err
+	}
+
+	results := make([]*pb.BatchResult, len(req.Items))
+	for i := range req.Items {
+		results[i] = protoconv.BatchResult(conversions[i], errs[i])
+	}
+
+	return &pb.BatchConvertResponse{Results: results}, nil
+}
+
+func (s *Server) GetRates(ctx context.Context, req *pb.GetRatesRequest) (*pb.RateTable, error) {
+	date, err := parseDate(req.Date)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	table, err := s.rateFetcher.GetRates(baseOrDefault(req.Base), req.Symbols, date)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return protoconv.RateTable(table), nil
+}
+
+func (s *Server) WatchRates(req *pb.WatchRatesRequest, stream pb.ExchangeRateService_WatchRatesServer) error {
+	base := baseOrDefault(req.Base)
+
+	updates, cancel := s.rateFetcher.WatchRates()
+	defer cancel()
+
+	table, err := s.rateFetcher.GetRates(base, req.Symbols, nil)
+	if err != nil {
+		return toStatus(err)
+	}
+	if err := stream.Send(protoconv.RateTable(table)); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case snapshot := <-updates:
+			table, err := s.rateFetcher.RebaseSnapshot(base, req.Symbols, snapshot)
+			if err != nil {
+				return toStatus(err)
+			}
+			if err := stream.Send(protoconv.RateTable(table)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// conversionRequest reads a conversion request, forward or reverse like the
+// HTTP /convert endpoint. A reverse request names the target amount and
+// the currency to pay in.
+func conversionRequest(req *pb.ConvertRequest) (service.ConversionRequest, error) {
+	if req.From == "" {
+		return service.ConversionRequest{}, appErrors.MissingParameterError("from")
+	}
+
+	if req.To == "" {
+		return service.ConversionRequest{}, appErrors.MissingParameterError("to")
+	}
+
+	if req.Amount != "" && req.TargetAmount != "" {
+		return service.ConversionRequest{}, appErrors.ConflictingParametersError("amount", "target_amount")
+	}
+
+	if req.Amount == "" && req.TargetAmount == "" {
+		return service.ConversionRequest{}, appErrors.MissingParameterError("amount")
+	}
+
+	date, err := parseDate(req.Date)
+	if err != nil {
+		return service.ConversionRequest{}, err
+	}
+
+	if req.TargetAmount != "" {
+		target, err := money.Parse(req.TargetAmount, req.To)
+		if err != nil {
+			return service.ConversionRequest{}, err
+		}
+
+		return service.ConversionRequest{Amount: target, To: req.From, Date: date, Reverse: true}, nil
+	}
+
+	amount, err := money.Parse(req.Amount, req.From)
+	if err != nil {
+		return service.ConversionRequest{}, err
+	}
+
+	precision := ""
+	if req.Precision != nil {
+		precision = strconv.Itoa(int(*req.Precision))
+	}
+
+	rounding, err := money.ParseRounding(req.Rounding, precision)
+	if err != nil {
+		return service.ConversionRequest{}, err
+	}
+
+	return service.ConversionRequest{Amount: amount, To: req.To, Date: date, Rounding: rounding}, nil
+}
+
+func parseDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, appErrors.InvalidDateFormatError()
+	}
+
+	return &date, nil
+}
+
+func baseOrDefault(base string) string {
+	if base == "" {
+		return defaultBase
+	}
+
+	return base
+}
+
+// toStatus maps err to a gRPC status by its CustomError category, with the
+// ErrorCode attached as a google.rpc.ErrorInfo reason.
+func toStatus(err error) error {
+	var customErr *appErrors.CustomError
+	if !errors.As(err, &customErr) {
+		return status.Error(codes.Internal, "An unexpected error occured")
+	}
+
+	st := status.New(categoryCode(customErr.Category), customErr.Message)
+	if detailed, detailErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: string(customErr.Code),
+		Domain: errorDomain,
+	}); detailErr == nil {
+		st = detailed
+	}
+
+	return st.Err()
+}
+
+func categoryCode(category appErrors.ErrorCategory) codes.Code {
+	switch category {
+	case appErrors.CategoryValidation, appErrors.CategoryFormat:
+		return codes.InvalidArgument
+	case appErrors.CategoryAuth:
+		return codes.Unauthenticated
+	case appErrors.CategoryForbidden:
+		return codes.PermissionDenied
+	case appErrors.CategoryNotFound:
+		return codes.NotFound
+	case appErrors.CategoryQuota, appErrors.CategoryTooLarge:
+		return codes.ResourceExhausted
+	case appErrors.CategoryAPI, appErrors.CategoryUnavailable:
+		return codes.Unavailable
+	case appErrors.CategoryTimeout:
+		return codes.DeadlineExceeded
+	default:
+		return codes.Internal
+	}
+}
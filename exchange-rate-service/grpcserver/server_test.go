@@ -0,0 +1,120 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+	pb "github.com/yourusername/exchange-rate-service/proto/exchangerate/v1"
+	"github.com/yourusername/exchange-rate-service/service"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{appErrors.UnsupportedCurrencyError("XYZ"), codes.InvalidArgument},
+		{appErrors.APIFetchError(nil), codes.Unavailable},
+		{appErrors.RatesUnavailableError(nil), codes.Unavailable},
+		{appErrors.UpstreamTimeoutError(nil), codes.DeadlineExceeded},
+		{appErrors.QuotaExceededError("daily limit"), codes.ResourceExhausted},
+		{appErrors.MissingRateError("EUR"), codes.Internal},
+	}
+
+	for _, tt := range tests {
+		st := status.Convert(toStatus(tt.err))
+		if st.Code() != tt.code {
+			t.Errorf("%v: code %s, expected %s", tt.err, st.Code(), tt.code)
+		}
+
+		details := st.Details()
+		info, ok := details[0].(*errdetails.ErrorInfo)
+		if len(details) != 1 || !ok {
+			t.Fatalf("Expected ErrorInfo detail, got %v", details)
+		}
+		if info.Reason != string(tt.err.(*appErrors.CustomError).Code) {
+			t.Errorf("Reason = %s, expected %s", info.Reason, tt.err.(*appErrors.CustomError).Code)
+		}
+	}
+}
+
+func TestCategoryCodeCoversEveryErrorCode(t *testing.T) {
+	expected := map[appErrors.ErrorCategory]codes.Code{
+		appErrors.CategoryValidation:  codes.InvalidArgument,
+		appErrors.CategoryAuth:        codes.Unauthenticated,
+		appErrors.CategoryForbidden:   codes.PermissionDenied,
+		appErrors.CategoryNotFound:    codes.NotFound,
+		appErrors.CategoryFormat:      codes.InvalidArgument,
+		appErrors.CategoryTooLarge:    codes.ResourceExhausted,
+		appErrors.CategoryQuota:       codes.ResourceExhausted,
+		appErrors.CategoryAPI:         codes.Unavailable,
+		appErrors.CategoryUnavailable: codes.Unavailable,
+		appErrors.CategoryTimeout:     codes.DeadlineExceeded,
+		appErrors.CategoryInternal:    codes.Internal,
+	}
+
+	for _, info := range appErrors.Codes {
+		code, ok := expected[info.Category]
+		if !ok {
+			t.Errorf("%s: category %s has no expected gRPC code", info.Code, info.Category)
+			continue
+		}
+		if got := categoryCode(info.Category); got != code {
+			t.Errorf("%s: category %s maps to %s, expected %s", info.Code, info.Category, got, code)
+		}
+	}
+}
+
+func TestConvertValidation(t *testing.T) {
+	t.Setenv("API_KEY", "test")
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	pb.RegisterExchangeRateServiceServer(server, NewServer(service.NewRateFetcherService()))
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer conn.Close()
+
+	client := pb.NewExchangeRateServiceClient(conn)
+
+	_, err = client.Convert(context.Background(), &pb.ConvertRequest{From: "USD", Amount: "10"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for missing to, got %v", err)
+	}
+
+	_, err = client.Convert(context.Background(), &pb.ConvertRequest{From: "USD", To: "EUR", Amount: "10", TargetAmount: "5"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for conflicting amounts, got %v", err)
+	}
+
+	_, err = client.BatchConvert(context.Background(), &pb.BatchConvertRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for empty batch, got %v", err)
+	}
+
+	resp, err := client.BatchConvert(context.Background(), &pb.BatchConvertRequest{
+		Items: []*pb.ConvertRequest{{To: "EUR", Amount: "1"}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.Results[0].GetError().GetCode() != string(appErrors.ErrMissingParameter) {
+		t.Errorf("Expected per-item MISSING_PARAMETER, got %v", resp.Results[0])
+	}
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+	"github.com/yourusername/exchange-rate-service/service"
+)
+
+type AlertHandler struct {
+	alerts *service.AlertService
+}
+
+func NewAlertHandler(alerts *service.AlertService) *AlertHandler {
+	return &AlertHandler{
+		alerts: alerts,
+	}
+}
+
+// alertRequest is the body of POST /alerts and PUT /alerts/:id. Threshold
+// accepts both JSON numbers and numeric strings.
+type alertRequest struct {
+	Pair       string          `json:"pair"`
+	Condition  string          `json:"condition"`
+	Threshold  json.RawMessage `json:"threshold"`
+	Cooldown   string          `json:"cooldown"`
+	WebhookURL string          `json:"webhook_url"`
+}
+
+func (h *AlertHandler) HandleList(c *gin.Context) {
+	rules := h.alerts.Rules()
+
+	alerts := make([]gin.H, len(rules))
+	for i, rule := range rules {
+		alerts[i] = alertResponse(rule)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"alerts": alerts})
+}
+
+// HandleCreate saves a new rule. The response is the only one that
+// includes the webhook signing secret.
+func (h *AlertHandler) HandleCreate(c *gin.Context) {
+	spec, err := parseAlertRequest(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	rule, err := h.alerts.CreateRule(spec)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	response := alertResponse(rule)
+	response["secret"] = rule.Secret
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h *AlertHandler) HandleGet(c *gin.Context) {
+	rule, err := h.alerts.Rule(c.Param("id"))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, alertResponse(rule))
+}
+
+func (h *AlertHandler) HandleUpdate(c *gin.Context) {
+	spec, err := parseAlertRequest(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	rule, err := h.alerts.UpdateRule(c.Param("id"), spec)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, alertResponse(rule))
+}
+
+func (h *AlertHandler) HandleDelete(c *gin.Context) {
+	if err := h.alerts.DeleteRule(c.Param("id")); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// HandleDeadLetters lists recent webhook deliveries that failed after
+// every retry.
+func (h *AlertHandler) HandleDeadLetters(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"dead_letters": h.alerts.DeadLetters()})
+}
+
+func parseAlertRequest(c *gin.Context) (service.AlertRuleSpec, error) {
+	var request alertRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&request); err != nil {
+		return service.AlertRuleSpec{}, appErrors.InvalidRequestBodyError(err)
+	}
+
+	spec := service.AlertRuleSpec{
+		Pair:       request.Pair,
+		Condition:  request.Condition,
+		Threshold:  amountString(request.Threshold),
+		Cooldown:   request.Cooldown,
+		WebhookURL: request.WebhookURL,
+	}
+
+	required := []struct{ name, value string }{
+		{"pair", spec.Pair},
+		{"condition", spec.Condition},
+		{"threshold", spec.Threshold},
+		{"webhook_url", spec.WebhookURL},
+	}
+	for _, field := range required {
+		if field.value == "" {
+			return service.AlertRuleSpec{}, appErrors.MissingParameterError(field.name)
+		}
+	}
+
+	return spec, nil
+}
+
+func alertResponse(rule service.AlertRule) gin.H {
+	response := gin.H{
+		"id":          rule.ID,
+		"pair":        rule.Pair.String(),
+		"condition":   rule.Condition,
+		"threshold":   rule.Threshold.String(),
+		"cooldown":    rule.Cooldown.String(),
+		"webhook_url": rule.WebhookURL,
+		"created_at":  formatTime(rule.CreatedAt),
+		"updated_at":  formatTime(rule.UpdatedAt),
+	}
+
+	if !rule.LastRate.IsZero() {
+		response["last_rate"] = rule.LastRate.String()
+	}
+
+	if !rule.LastTriggered.IsZero() {
+		response["last_triggered_at"] = formatTime(rule.LastTriggered)
+	}
+
+	return response
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+	"github.com/yourusername/exchange-rate-service/service"
+)
+
+type APIKeyHandler struct {
+	keys *service.APIKeyService
+}
+
+func NewAPIKeyHandler(keys *service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		keys: keys,
+	}
+}
+
+// apiKeyRequest is the body of POST /keys.
+type apiKeyRequest struct {
+	Name       string   `json:"name"`
+	Scopes     []string `json:"scopes"`
+	DailyQuota int      `json:"daily_quota"`
+	ExpiresAt  string   `json:"expires_at"`
+}
+
+func (h *APIKeyHandler) HandleList(c *gin.Context) {
+	keys := h.keys.Keys()
+
+	response := make([]gin.H, len(keys))
+	for i, key := range keys {
+		response[i] = apiKeyResponse(key)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"keys": response})
+}
+
+// HandleIssue creates a key. The response is the only one that includes
+// the key itself.
+func (h *APIKeyHandler) HandleIssue(c *gin.Context) {
+	var request apiKeyRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&request); err != nil {
+		respondWithError(c, appErrors.InvalidRequestBodyError(err))
+		return
+	}
+
+	if request.Name == "" {
+		respondWithError(c, appErrors.MissingParameterError("name"))
+		return
+	}
+
+	key, secret, err := h.keys.IssueKey(service.APIKeySpec{
+		Name:       request.Name,
+		Scopes:     request.Scopes,
+		DailyQuota: request.DailyQuota,
+		ExpiresAt:  request.ExpiresAt,
+	})
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	response := apiKeyResponse(key)
+	response["key"] = secret
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h *APIKeyHandler) HandleGet(c *gin.Context) {
+	key, err := h.keys.Key(c.Param("id"))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiKeyResponse(key))
+}
+
+// HandleRotate issues a new secret for a key. Like HandleIssue, the
+// response is the only one that includes it.
+func (h *APIKeyHandler) HandleRotate(c *gin.Context) {
+	key, secret, err := h.keys.RotateKey(c.Param("id"))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	response := apiKeyResponse(key)
+	response["key"] = secret
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *APIKeyHandler) HandleRevoke(c *gin.Context) {
+	if err := h.keys.RevokeKey(c.Param("id")); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func apiKeyResponse(key service.APIKey) gin.H {
+	response := gin.H{
+		"id":          key.ID,
+		"name":        key.Name,
+		"prefix":      key.Prefix,
+		"scopes":      key.Scopes,
+		"daily_quota": key.DailyQuota,
+		"created_at":  formatTime(key.CreatedAt),
+		"usage": gin.H{
+			"today":     key.UsedOn(time.Now()),
+			"total":     key.Usage.Total,
+			"last_used": formatTime(key.Usage.LastUsed),
+		},
+	}
+
+	for field, t := range map[string]time.Time{
+		"expires_at": key.ExpiresAt,
+		"rotated_at": key.RotatedAt,
+		"revoked_at": key.RevokedAt,
+	} {
+		if !t.IsZero() {
+			response[field] = formatTime(t)
+		}
+	}
+
+	return response
+}
@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+	"github.com/yourusername/exchange-rate-service/service"
+)
+
+const (
+	apiKeyHeader = "X-API-Key"
+	apiKeyQuery  = "api_key"
+
+	bearerPrefix = "Bearer "
+
+	apiKeyContextKey    = "apiKey"
+	principalContextKey = "principal"
+	// subjectContextKey holds the bearer token subject for request logs.
+	subjectContextKey = "subject"
+	// credentialCheckContextKey holds the credentialCheck of the request.
+	credentialCheckContextKey = "credentialCheck"
+)
+
+// credentialCheck checks the credentials a request was authenticated with
+// again, for connections that outlive the request.
+type credentialCheck func(ctx context.Context) error
+
+// Authenticator checks the API key or bearer token of requests to routes
+// that require a scope, and counts requests made with API keys against
+// their daily quota.
+type Authenticator struct {
+	keys    *service.APIKeyService
+	tokens  *service.TokenVerifier
+	limiter *service.RateLimiter
+}
+
+// NewAuthenticator accepts API keys checked by keys, and bearer tokens
+// checked by tokens. A nil tokens rejects bearer tokens. Failed attempts
+// are limited per IP address by limiter, unless it is nil.
+func NewAuthenticator(keys *service.APIKeyService, tokens *service.TokenVerifier, limiter *service.RateLimiter) *Authenticator {
+	return &Authenticator{
+		keys:    keys,
+		tokens:  tokens,
+		limiter: limiter,
+	}
+}
+
+// Require rejects requests without an active key or a valid bearer token
+// granting scope, or whose key has used up its daily quota. The key or
+// token principal is stored in the context for later handlers. The request
+// is not counted against the quota; ChargeUsage does that.
+func (a *Authenticator) Require(scope service.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !a.allowAttempt(c) {
+			return
+		}
+
+		if token, ok := bearerToken(c); ok {
+			a.requireToken(c, token, scope)
+			return
+		}
+
+		secret := c.GetHeader(apiKeyHeader)
+		if secret == "" {
+			secret = c.Query(apiKeyQuery)
+		}
+
+		if secret == "" {
+			a.reject(c, appErrors.UnauthorizedError())
+			return
+		}
+
+		key, err := a.keys.Authenticate(secret, scope)
+		if err != nil {
+			a.reject(c, err)
+			return
+		}
+
+		c.Set(apiKeyContextKey, key)
+		c.Set(credentialCheckContextKey, credentialCheck(func(context.Context) error {
+			_, err := a.keys.Authenticate(secret, scope)
+			return err
+		}))
+		c.Next()
+	}
+}
+
+// ChargeUsage counts requests authenticated with an API key against its
+// daily quota. It runs after rate limiting and validation, so requests
+// rejected by them are not charged.
+func (a *Authenticator) ChargeUsage() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, ok := requestAPIKey(c)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		key, err := a.keys.ChargeUsage(key)
+		if err != nil {
+			a.reject(c, err)
+			return
+		}
+
+		c.Set(apiKeyContextKey, key)
+		c.Next()
+	}
+}
+
+func (a *Authenticator) requireToken(c *gin.Context, token string, scope service.Scope) {
+	if a.tokens == nil {
+		a.reject(c, appErrors.UnauthorizedError())
+		return
+	}
+
+	principal, err := a.tokens.Verify(c.Request.Context(), token, scope)
+	if principal.Subject != "" {
+		c.Set(subjectContextKey, principal.Subject)
+	}
+	if err != nil {
+		a.reject(c, err)
+		return
+	}
+
+	c.Set(principalContextKey, principal)
+	c.Set(credentialCheckContextKey, credentialCheck(func(ctx context.Context) error {
+		_, err := a.tokens.Verify(ctx, token, scope)
+		return err
+	}))
+	c.Next()
+}
+
+// allowAttempt rejects requests from an IP address that has failed to
+// authenticate too often with 429 and a Retry-After. When the store fails
+// the request is let through.
+func (a *Authenticator) allowAttempt(c *gin.Context) bool {
+	if a.limiter == nil {
+		return true
+	}
+
+	result, err := a.limiter.AllowAuthAttempt(c.Request.Context(), "ip:"+c.ClientIP())
+	if err != nil {
+		log.Printf("Auth failure limit check failed, allowing request: %v", err)
+		return true
+	}
+	if result == nil || result.Allowed {
+		return true
+	}
+
+	c.Header("Retry-After", strconv.Itoa(max(1, ceilSeconds(result.RetryAfter))))
+	respondWithError(c, appErrors.RateLimitedError(result.Bucket))
+	c.Abort()
+	return false
+}
+
+// reject responds with err. Missing or rejected credentials are charged to
+// the client IP's failed attempts, and a used up quota gets a Retry-After of the
+// seconds until it resets.
+func (a *Authenticator) reject(c *gin.Context, err error) {
+	var customErr *appErrors.CustomError
+	if a.limiter != nil && errors.As(err, &customErr) && customErr.Category == appErrors.CategoryAuth {
+		if err := a.limiter.AuthFailed(c.Request.Context(), "ip:"+c.ClientIP()); err != nil {
+			log.Printf("Failed to record auth failure: %v", err)
+		}
+	}
+
+	if errors.Is(err, &appErrors.CustomError{Code: appErrors.ErrQuotaExceeded}) {
+		retryAfter := time.Until(service.QuotaResetsAt(time.Now()))
+		c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+	}
+
+	respondWithError(c, err)
+	c.Abort()
+}
+
+// bearerToken reads the token of an Authorization: Bearer header.
+func bearerToken(c *gin.Context) (string, bool) {
+	header := c.GetHeader("Authorization")
+	if len(header) < len(bearerPrefix) || !strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+		return "", false
+	}
+
+	return strings.TrimSpace(header[len(bearerPrefix):]), true
+}
+
+// requestAPIKey returns the key the request was authenticated with.
+func requestAPIKey(c *gin.Context) (service.APIKey, bool) {
+	value, ok := c.Get(apiKeyContextKey)
+	if !ok {
+		return service.APIKey{}, false
+	}
+
+	key, ok := value.(service.APIKey)
+	return key, ok
+}
+
+// requestPrincipal returns the bearer token principal the request was
+// authenticated with.
+func requestPrincipal(c *gin.Context) (service.Principal, bool) {
+	value, ok := c.Get(principalContextKey)
+	if !ok {
+		return service.Principal{}, false
+	}
+
+	principal, ok := value.(service.Principal)
+	return principal, ok
+}
+
+// requestCredentialCheck returns the check of the credentials the request
+// was authenticated with.
+func requestCredentialCheck(c *gin.Context) (credentialCheck, bool) {
+	value, ok := c.Get(credentialCheckContextKey)
+	if !ok {
+		return nil, false
+	}
+
+	check, ok := value.(credentialCheck)
+	return check, ok
+}
+
+// authenticated reports whether the request passed Require with an API key
+// or a bearer token.
+func authenticated(c *gin.Context) bool {
+	_, hasKey := requestAPIKey(c)
+	_, hasPrincipal := requestPrincipal(c)
+
+	return hasKey || hasPrincipal
+}
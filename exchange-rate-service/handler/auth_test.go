@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/yourusername/exchange-rate-service/service"
+)
+
+func TestAuthenticatorRequire(t *testing.T) {
+	t.Setenv("API_KEYS_FILE", "")
+	t.Setenv("API_ADMIN_KEY", "")
+	keys := service.NewAPIKeyService()
+
+	_, secret, err := keys.IssueKey(service.APIKeySpec{Name: "app", Scopes: []string{"rates"}, DailyQuota: 2})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	auth := NewAuthenticator(keys, nil, nil)
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+	r.GET("/rates", auth.Require(service.ScopeRates), auth.ChargeUsage(), ok)
+	r.GET("/keys", auth.Require(service.ScopeAdmin), auth.ChargeUsage(), ok)
+
+	tests := []struct {
+		url    string
+		header string
+		status int
+	}{
+		{"/rates", "", http.StatusUnauthorized},
+		{"/rates", "erk_wrong", http.StatusUnauthorized},
+		{"/rates", secret, http.StatusNoContent},
+		{"/keys", secret, http.StatusForbidden},
+		{"/rates?api_key=" + secret, "", http.StatusNoContent},
+		{"/rates", secret, http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+		if tt.header != "" {
+			req.Header.Set("X-API-Key", tt.header)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s with %q: status %d, expected %d", tt.url, tt.header, w.Code, tt.status)
+		}
+
+		if tt.status == http.StatusTooManyRequests {
+			if seconds, err := strconv.Atoi(w.Header().Get("Retry-After")); err != nil || seconds <= 0 || seconds > 86400 {
+				t.Errorf("Retry-After = %q, expected seconds until midnight UTC", w.Header().Get("Retry-After"))
+			}
+		}
+	}
+}
+
+func TestAuthenticatorLimitsFailedAttempts(t *testing.T) {
+	t.Setenv("API_KEYS_FILE", "")
+	t.Setenv("API_ADMIN_KEY", "")
+	t.Setenv("RATE_LIMIT_AUTH_FAILURES", "2/1m")
+	t.Setenv("RATE_LIMIT_REDIS_URL", "")
+	keys := service.NewAPIKeyService()
+
+	_, secret, err := keys.IssueKey(service.APIKeySpec{Name: "app", Scopes: []string{"rates"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	auth := NewAuthenticator(keys, nil, service.NewRateLimiter())
+	r.GET("/rates", auth.Require(service.ScopeRates), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	tests := []struct {
+		addr   string
+		key    string
+		status int
+	}{
+		{"192.0.2.1:1234", "", http.StatusUnauthorized},
+		{"192.0.2.1:1234", secret, http.StatusNoContent},
+		{"192.0.2.1:1234", "erk_wrong", http.StatusUnauthorized},
+		// Two failures use up the bucket, even for a valid key.
+		{"192.0.2.1:1234", secret, http.StatusTooManyRequests},
+		{"192.0.2.2:1234", secret, http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/rates", nil)
+		req.RemoteAddr = tt.addr
+		if tt.key != "" {
+			req.Header.Set("X-API-Key", tt.key)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s with %.12q: status %d, expected %d", tt.addr, tt.key, w.Code, tt.status)
+		}
+		if tt.status == http.StatusTooManyRequests && w.Header().Get("Retry-After") == "" {
+			t.Errorf("%s: expected a Retry-After header", tt.addr)
+		}
+	}
+}
+
+func TestAuthenticatorRequireBearerToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	encode := func(n *big.Int) string { return base64.RawURLEncoding.EncodeToString(n.Bytes()) }
+	jwks := fmt.Sprintf(`{"keys":[{"kty":"RSA","kid":"local","n":%q,"e":%q}]}`,
+		encode(key.N), encode(big.NewInt(int64(key.E))))
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, []byte(jwks), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	t.Setenv("OIDC_JWKS_FILE", path)
+	t.Setenv("OIDC_JWKS_URL", "")
+	t.Setenv("OIDC_ISSUER", "https://issuer.example.com")
+	t.Setenv("OIDC_AUDIENCE", "exchange-rate-service")
+	t.Setenv("OIDC_ROLES_CLAIM", "")
+	t.Setenv("OIDC_ROLE_SCOPES", "fx-reader=rates")
+	t.Setenv("API_KEYS_FILE", "")
+	t.Setenv("API_ADMIN_KEY", "")
+
+	sign := func(claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "local"
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return signed
+	}
+	claims := func(exp time.Time) jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":   "https://issuer.example.com",
+			"aud":   "exchange-rate-service",
+			"sub":   "svc-ledger",
+			"exp":   exp.Unix(),
+			"roles": []string{"fx-reader"},
+		}
+	}
+	valid := sign(claims(time.Now().Add(time.Hour)))
+	expired := sign(claims(time.Now().Add(-time.Hour)))
+
+	var logs bytes.Buffer
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(gin.LoggerWithConfig(gin.LoggerConfig{Formatter: formatRequestLog, Output: &logs}))
+	auth := NewAuthenticator(service.NewAPIKeyService(), service.NewTokenVerifier(), nil)
+	ok := func(c *gin.Context) {
+		principal, _ := requestPrincipal(c)
+		c.String(http.StatusOK, principal.Subject)
+	}
+	r.GET("/rates", auth.Require(service.ScopeRates), ok)
+	r.GET("/convert", auth.Require(service.ScopeConvert), ok)
+
+	tests := []struct {
+		url    string
+		header string
+		status int
+	}{
+		{"/rates", "Bearer " + valid, http.StatusOK},
+		{"/rates", "bearer " + valid, http.StatusOK},
+		{"/convert", "Bearer " + valid, http.StatusForbidden},
+		{"/rates", "Bearer " + expired, http.StatusUnauthorized},
+		{"/rates", "Bearer garbage", http.StatusUnauthorized},
+		{"/rates", "Basic dXNlcjpwYXNz", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+		req.Header.Set("Authorization", tt.header)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s with %.20q: status %d, expected %d", tt.url, tt.header, w.Code, tt.status)
+		}
+		if tt.status == http.StatusOK && w.Body.String() != "svc-ledger" {
+			t.Errorf("%s: principal subject %q, expected svc-ledger", tt.url, w.Body.String())
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	if len(lines) != len(tests) || !strings.HasSuffix(lines[0], "sub=svc-ledger") ||
+		!strings.HasSuffix(lines[2], "sub=svc-ledger") || !strings.HasSuffix(lines[4], "sub=-") {
+		t.Errorf("Request logs do not carry the token subject:\n%s", logs.String())
+	}
+
+	noTokens := gin.New()
+	noTokens.GET("/rates", NewAuthenticator(service.NewAPIKeyService(), nil, nil).Require(service.ScopeRates), ok)
+	req := httptest.NewRequest(http.MethodGet, "/rates", nil)
+	req.Header.Set("Authorization", "Bearer "+valid)
+	w := httptest.NewRecorder()
+	noTokens.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Bearer token without a JWKS: status %d, expected 401", w.Code)
+	}
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+	"github.com/yourusername/exchange-rate-service/money"
+	pb "github.com/yourusername/exchange-rate-service/proto/exchangerate/v1"
+	"github.com/yourusername/exchange-rate-service/protoconv"
+	"github.com/yourusername/exchange-rate-service/service"
+)
+
+// batchItem is one conversion in a POST /convert/batch body. Amount accepts
+// both JSON numbers and numeric strings.
+type batchItem struct {
+	From   string          `json:"from"`
+	To     string          `json:"to"`
+	Amount json.RawMessage `json:"amount"`
+	Date   string          `json:"date"`
+}
+
+// HandleBatch converts a JSON array of conversions. Each item gets its own
+// result or error, so invalid items do not fail the batch. The response is
+// rendered in the negotiated format, with one CSV record per item.
+func (h *ConvertHandler) HandleBatch(c *gin.Context) {
+	items, err := batchItems(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	if len(items) == 0 || len(items) > service.MaxBatchSize {
+		respondWithError(c, appErrors.BatchTooLargeError(service.MaxBatchSize))
+		return
+	}
+
+	locale, err := requestLocale(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	rounding, err := parseRounding(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	errs := make([]error, len(items))
+	var requests []service.ConversionRequest
+	var indexes []int
+	for i, item := range items {
+		request, err := parseBatchItem(item)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		requests = append(requests, request)
+		indexes = append(indexes, i)
+	}
+
+	conversions := make([]*service.Conversion, len(items))
+	for j, result := range h.rateFetcher.ConvertBatch(requests, rounding) {
+		conversions[indexes[j]], errs[indexes[j]] = result.Conversion, result.Err
+	}
+
+	results := make([]gin.H, len(items))
+	message := &pb.BatchConvertResponse{Results: make([]*pb.BatchResult, len(items))}
+	rows := [][]string{{"index", "from", "to", "amount", "result", "rate", "code", "detail"}}
+	failed := 0
+	for i, item := range items {
+		message.Results[i] = protoconv.BatchResult(conversions[i], errs[i])
+
+		if errs[i] != nil {
+			_, body := errorResponse(errs[i])
+			body["index"] = i
+			results[i] = body
+			failed++
+
+			problem := message.Results[i].GetError()
+			rows = append(rows, []string{strconv.Itoa(i), item.From, item.To, amountString(item.Amount), "", "", problem.Code, problem.Message})
+			continue
+		}
+
+		response := conversionResponse(conversions[i], locale, rounding)
+		response["index"] = i
+		results[i] = response
+
+		conversion := message.Results[i].GetConversion()
+		conversion.Result = response["result"].(string)
+		rows = append(rows, []string{strconv.Itoa(i), conversion.From, conversion.To, conversion.Amount, conversion.Result, conversion.Rate, "", ""})
+	}
+
+	render(c, http.StatusOK, Body{
+		Fields: gin.H{
+			"results":   results,
+			"succeeded": len(items) - failed,
+			"failed":    failed,
+		},
+		Rows:    rows,
+		Message: message,
+	})
+}
+
+func parseBatchItem(item batchItem) (service.ConversionRequest, error) {
+	if item.From == "" {
+		return service.ConversionRequest{}, appErrors.MissingParameterError("from")
+	}
+
+	if item.To == "" {
+		return service.ConversionRequest{}, appErrors.MissingParameterError("to")
+	}
+
+	amountStr := amountString(item.Amount)
+	if amountStr == "" {
+		return service.ConversionRequest{}, appErrors.MissingParameterError("amount")
+	}
+
+	amount, err := money.Parse(amountStr, item.From)
+	if err != nil {
+		return service.ConversionRequest{}, err
+	}
+
+	date, err := parseDate(item.Date)
+	if err != nil {
+		return service.ConversionRequest{}, err
+	}
+
+	return service.ConversionRequest{Amount: amount, To: item.To, Date: date}, nil
+}
+
+// amountString returns a JSON number or string amount as text, or "" when
+// it is absent or null.
+func amountString(raw json.RawMessage) string {
+	var value string
+	if err := json.Unmarshal(raw, &value); err == nil {
+		return value
+	}
+
+	if string(raw) == "null" {
+		return ""
+	}
+
+	return string(raw)
+}
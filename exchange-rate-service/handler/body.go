@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+)
+
+// maxBodyBytes caps request bodies. A full batch of short items fits well
+// within it.
+const maxBodyBytes = 1 << 20
+
+const (
+	bodyContextKey = "body"
+	// bodyValueContextKey and batchItemsContextKey hold the body once it
+	// has been decoded, so each form is only decoded once per request.
+	bodyValueContextKey  = "bodyValue"
+	batchItemsContextKey = "batchItems"
+)
+
+// readBody reads the request body, up to maxBodyBytes, once for every
+// middleware and handler after it, which get it from bodyBytes. Larger
+// bodies are rejected with REQUEST_TOO_LARGE.
+func readBody() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBodyBytes))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				err = appErrors.RequestTooLargeError(maxBodyBytes)
+			} else {
+				err = appErrors.InvalidRequestBodyError(err)
+			}
+
+			respondWithError(c, err)
+			c.Abort()
+			return
+		}
+
+		c.Set(bodyContextKey, body)
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
+
+// bodyBytes returns the body readBody read.
+func bodyBytes(c *gin.Context) []byte {
+	value, _ := c.Get(bodyContextKey)
+	body, _ := value.([]byte)
+
+	return body
+}
+
+// bodyValue decodes the body as generic JSON, keeping numbers as
+// json.Number.
+func bodyValue(c *gin.Context) (any, error) {
+	if value, ok := c.Get(bodyValueContextKey); ok {
+		return value, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(bodyBytes(c)))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, appErrors.InvalidRequestBodyError(err)
+	}
+
+	c.Set(bodyValueContextKey, value)
+	return value, nil
+}
+
+// batchItems decodes the body as a POST /convert/batch array.
+func batchItems(c *gin.Context) ([]batchItem, error) {
+	if items, ok := c.Get(batchItemsContextKey); ok {
+		return items.([]batchItem), nil
+	}
+
+	var items []batchItem
+	if err := json.Unmarshal(bodyBytes(c), &items); err != nil {
+		return nil, appErrors.InvalidRequestBodyError(err)
+	}
+
+	c.Set(batchItemsContextKey, items)
+	return items, nil
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+	"github.com/yourusername/exchange-rate-service/metrics"
+	"github.com/yourusername/exchange-rate-service/money"
+	"golang.org/x/text/language"
+)
+
+func parseDate(dateStr string) (*time.Time, error) {
+	if dateStr == "" {
+		return nil, nil
+	}
+
+	parsedDate, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return nil, appErrors.InvalidDateFormatError()
+	}
+
+	return &parsedDate, nil
+}
+
+// requestLocale reads the locale from the locale query parameter, falling back
+// to the preferred Accept-Language entry. It returns nil when neither is set.
+func requestLocale(c *gin.Context) (*language.Tag, error) {
+	if locale := c.Query("locale"); locale != "" {
+		tag, err := language.Parse(locale)
+		if err != nil {
+			return nil, appErrors.InvalidLocaleError(locale)
+		}
+		return &tag, nil
+	}
+
+	tags, _, err := language.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+	if err != nil || len(tags) == 0 {
+		return nil, nil
+	}
+
+	return &tags[0], nil
+}
+
+// parseRounding reads the rounding and precision query parameters.
+func parseRounding(c *gin.Context) (money.Rounding, error) {
+	return money.ParseRounding(c.Query("rounding"), c.Query("precision"))
+}
+
+// respondWithError writes err as an RFC 7807 problem in the format the
+// request asked for, with the request path as its instance. Caching headers
+// set for a successful response are dropped.
+func respondWithError(c *gin.Context, err error) {
+	c.Set(metrics.ErrorCodeKey, metrics.ErrorCode(err))
+	for _, header := range []string{"ETag", "Last-Modified", "Cache-Control"} {
+		c.Writer.Header().Del(header)
+	}
+
+	status, body := errorBody(err)
+	body.Fields["instance"] = c.Request.URL.Path
+	render(c, status, body)
+}
+
+// errorResponse maps err to its HTTP status and an RFC 7807 problem. The
+// first CustomError in err's chain gives the status and code, and its type
+// links to the code's documentation. Other errors are reported as a
+// generic internal error without a code.
+func errorResponse(err error) (int, gin.H) {
+	var customErr *appErrors.CustomError
+	if !errors.As(err, &customErr) {
+		return http.StatusInternalServerError, gin.H{
+			"type":   "about:blank",
+			"title":  http.StatusText(http.StatusInternalServerError),
+			"status": http.StatusInternalServerError,
+			"detail": "An unexpected error occured",
+		}
+	}
+
+	status := customErr.GetHTTPStatus()
+	title := http.StatusText(status)
+	if info, ok := appErrors.Lookup(customErr.Code); ok {
+		title = info.Description
+	}
+
+	return status, gin.H{
+		"type":   problemType(customErr.Code),
+		"title":  title,
+		"status": status,
+		"detail": customErr.Message,
+		"code":   customErr.Code,
+	}
+}
+
+// problemType is the URI documenting code, served by RegisterRoutes.
+func problemType(code appErrors.ErrorCode) string {
+	return APIVersion + "/errors/" + string(code)
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/exchange-rate-service/service"
+)
+
+// notModified sets the ETag, Last-Modified and Cache-Control headers from
+// freshness and reports whether the request's If-None-Match or
+// If-Modified-Since shows the client already has this response. In that
+// case it has answered 304 and the handler should return. Each response
+// format gets its own ETag. Authenticated responses may only be cached by
+// the client, not by shared caches.
+func notModified(c *gin.Context, freshness service.Freshness) bool {
+	etag := freshness.ETag
+	if format := requestFormat(c); format != FormatJSON {
+		etag = strings.TrimSuffix(etag, `"`) + "." + string(format) + `"`
+	}
+
+	visibility := "public"
+	if authenticated(c) {
+		visibility = "private"
+		c.Writer.Header().Add("Vary", apiKeyHeader+", Authorization")
+	}
+
+	cacheControl := visibility + ", max-age=" + strconv.FormatInt(int64(freshness.MaxAge/time.Second), 10)
+	if freshness.Immutable {
+		cacheControl += ", immutable"
+	}
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", cacheControl)
+	if !freshness.LastModified.IsZero() {
+		c.Header("Last-Modified", freshness.LastModified.UTC().Format(http.TimeFormat))
+	}
+
+	// If-None-Match takes precedence over If-Modified-Since (RFC 9110 13.2.2).
+	if match := c.GetHeader("If-None-Match"); match != "" {
+		if !etagMatches(match, etag) {
+			return false
+		}
+	} else {
+		since, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+		if err != nil || freshness.LastModified.IsZero() || freshness.LastModified.Truncate(time.Second).After(since) {
+			return false
+		}
+	}
+
+	c.Writer.Header().Add("Vary", "Accept")
+	c.Status(http.StatusNotModified)
+	return true
+}
+
+// etagMatches compares an If-None-Match list with etag using the weak
+// comparison the header calls for.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
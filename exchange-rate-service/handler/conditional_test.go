@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/exchange-rate-service/service"
+)
+
+func TestNotModified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	modified := time.Date(2025, 11, 28, 10, 0, 0, 0, time.UTC)
+	freshness := service.Freshness{ETag: `W/"v42"`, LastModified: modified, MaxAge: 90 * time.Second}
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		status  int
+	}{
+		{"no validators", nil, http.StatusOK},
+		{"matching etag", map[string]string{"If-None-Match": `"v41", W/"v42"`}, http.StatusNotModified},
+		{"strong form of etag", map[string]string{"If-None-Match": `"v42"`}, http.StatusNotModified},
+		{"any", map[string]string{"If-None-Match": "*"}, http.StatusNotModified},
+		{"other etag", map[string]string{"If-None-Match": `W/"v41"`}, http.StatusOK},
+		{"not modified since", map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)}, http.StatusNotModified},
+		{"modified since", map[string]string{"If-Modified-Since": modified.Add(-time.Second).Format(http.TimeFormat)}, http.StatusOK},
+		{"etag wins", map[string]string{"If-None-Match": `W/"v41"`, "If-Modified-Since": modified.Format(http.TimeFormat)}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/v1/rates", nil)
+		for name, value := range tt.headers {
+			c.Request.Header.Set(name, value)
+		}
+
+		if !notModified(c, freshness) {
+			c.Status(http.StatusOK)
+		}
+		c.Writer.WriteHeaderNow()
+
+		if w.Code != tt.status {
+			t.Errorf("%s: status %d, expected %d", tt.name, w.Code, tt.status)
+		}
+		if w.Header().Get("ETag") != `W/"v42"` || w.Header().Get("Cache-Control") != "public, max-age=90" {
+			t.Errorf("%s: headers %v", tt.name, w.Header())
+		}
+		if w.Header().Get("Last-Modified") != "Fri, 28 Nov 2025 10:00:00 GMT" {
+			t.Errorf("%s: Last-Modified %s", tt.name, w.Header().Get("Last-Modified"))
+		}
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/v1/rates?date=2025-11-01", nil)
+	notModified(c, service.Freshness{ETag: `W/"2025-11-01"`, MaxAge: 365 * 24 * time.Hour, Immutable: true})
+	if w.Header().Get("Cache-Control") != "public, max-age=31536000, immutable" {
+		t.Errorf("Immutable Cache-Control = %s", w.Header().Get("Cache-Control"))
+	}
+}
+
+func TestNotModifiedPrivateWhenAuthenticated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/v1/rates", nil)
+	c.Set(apiKeyContextKey, service.APIKey{ID: "key"})
+	notModified(c, service.Freshness{ETag: `W/"v42"`, MaxAge: 90 * time.Second})
+
+	if w.Header().Get("Cache-Control") != "private, max-age=90" {
+		t.Errorf("Authenticated Cache-Control = %s", w.Header().Get("Cache-Control"))
+	}
+	if w.Header().Get("Vary") != "X-API-Key, Authorization" {
+		t.Errorf("Authenticated Vary = %s", w.Header().Get("Vary"))
+	}
+}
@@ -2,21 +2,33 @@ package handler
 
 import (
 	"net/http"
+	"os"
+	"strconv"
 	"time"
 
 	appErrors "github.com/yourusername/exchange-rate-service/errors"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yourusername/exchange-rate-service/money"
+	"github.com/yourusername/exchange-rate-service/protoconv"
 	"github.com/yourusername/exchange-rate-service/service"
+	"golang.org/x/text/language"
 )
 
 type ConvertHandler struct {
 	rateFetcher *service.RateFetcherService
+
+	// legacyResponse makes the bare {"amount": ...} body the default for
+	// clients written against the original API.
+	legacyResponse bool
 }
 
 func NewConvertHandler(rateFetcher *service.RateFetcherService) *ConvertHandler {
+	legacy, _ := strconv.ParseBool(os.Getenv("LEGACY_CONVERT_RESPONSE"))
+
 	return &ConvertHandler{
-		rateFetcher: rateFetcher,
+		rateFetcher:    rateFetcher,
+		legacyResponse: legacy,
 	}
 }
 
@@ -25,63 +37,169 @@ func (h *ConvertHandler) HandleConvert(c *gin.Context) {
 	from := c.Query("from")
 	to := c.Query("to")
 	amountStr := c.Query("amount")
+	targetStr := c.Query("target_amount")
 	dateStr := c.Query("date")
 
 	if from == "" {
-		h.respondWithError(c, appErrors.MissingParameterError("from"))
+		respondWithError(c, appErrors.MissingParameterError("from"))
 		return
 	}
 
 	if to == "" {
-		h.respondWithError(c, appErrors.MissingParameterError("to"))
+		respondWithError(c, appErrors.MissingParameterError("to"))
+		return
+	}
+
+	if amountStr != "" && targetStr != "" {
+		respondWithError(c, appErrors.ConflictingParametersError("amount", "target_amount"))
+		return
+	}
+
+	if amountStr == "" && targetStr == "" {
+		respondWithError(c, appErrors.MissingParameterError("amount"))
 		return
 	}
 
-	if amountStr == "" {
-		h.respondWithError(c, appErrors.MissingParameterError("amount"))
+	date, err := parseDate(dateStr)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	locale, err := requestLocale(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	// The formatted amounts follow Accept-Language when locale is not given.
+	c.Writer.Header().Add("Vary", "Accept-Language")
+
+	if targetStr != "" {
+		h.handleReverse(c, from, to, targetStr, date, locale)
+		return
+	}
+
+	amount, err := money.Parse(amountStr, from)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	rounding, err := parseRounding(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	conversion, err := h.rateFetcher.ConvertCurrency(amount, to, date, rounding)
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	var date *time.Time
-	if dateStr != "" {
-		parsedDate, err := time.Parse("2006-01-02", dateStr)
-		if err != nil {
-			h.respondWithError(c, appErrors.InvalidDateFormatError())
-			return
+	if notModified(c, h.rateFetcher.Freshness(conversion.Snapshot)) {
+		return
+	}
+
+	message := protoconv.Conversion(conversion)
+
+	if h.useLegacyResponse(c) {
+		response := gin.H{
+
+			"amount": conversion.Result.Amount().String(),
 		}
-		date = &parsedDate
+		addFormatted(response, "formatted", conversion.Result, locale)
+
+		render(c, http.StatusOK, Body{Fields: response, Message: message})
+		return
+	}
+
+	response := conversionResponse(conversion, locale, rounding)
+	message.Result = response["result"].(string)
+
+	render(c, http.StatusOK, Body{Fields: response, Message: message})
+
+}
+
+// handleReverse answers how much of from is needed to receive target of to.
+func (h *ConvertHandler) handleReverse(c *gin.Context, from, to, targetStr string, date *time.Time, locale *language.Tag) {
+	target, err := money.Parse(targetStr, to)
+	if err != nil {
+		respondWithError(c, err)
+		return
 	}
 
-	result, err := h.rateFetcher.ConvertCurrency(from, to, amountStr, date)
+	conversion, err := h.rateFetcher.ConvertCurrencyReverse(from, target, date)
 	if err != nil {
-		h.respondWithError(c, err)
+		respondWithError(c, err)
+		return
+	}
+
+	if notModified(c, h.rateFetcher.Freshness(conversion.Snapshot)) {
 		return
 	}
 
-	response := gin.H{
+	message := protoconv.Conversion(conversion)
 
-		"amount": result,
+	if h.useLegacyResponse(c) {
+		response := gin.H{
+			"source_amount": conversion.Amount.AmountString(),
+		}
+		addFormatted(response, "formatted", conversion.Amount, locale)
+
+		render(c, http.StatusOK, Body{Fields: response, Message: message})
+		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	response := conversionResponse(conversion, locale, money.Rounding{})
+	addFormatted(response, "formatted_amount", conversion.Amount, locale)
 
+	render(c, http.StatusOK, Body{Fields: response, Message: message})
 }
 
-func (h *ConvertHandler) respondWithError(c *gin.Context, err error) {
+// useLegacyResponse lets a legacy=true|false query parameter override the
+// server-wide default.
+func (h *ConvertHandler) useLegacyResponse(c *gin.Context) bool {
+	if legacy, err := strconv.ParseBool(c.Query("legacy")); err == nil {
+		return legacy
+	}
+
+	return h.legacyResponse
+}
 
-	customErr, ok := err.(*appErrors.CustomError)
+// conversionResponse builds the full conversion body. The result is shown
+// at full precision unless rounding was requested; an explicit precision
+// also fixes the number of decimals shown.
+func conversionResponse(conversion *service.Conversion, locale *language.Tag, rounding money.Rounding) gin.H {
+	result := conversion.Result.Amount().String()
+	switch {
+	case rounding.Places != nil:
+		result = conversion.Result.Amount().StringFixed(*rounding.Places)
+	case !rounding.IsZero():
+		result = conversion.Result.AmountString()
+	}
 
-	if ok {
-		c.JSON(customErr.GetHTTPStatus(), gin.H{
-			"error":        customErr.Code,
-			"errorMessage": customErr.Message,
-		})
+	response := snapshotResponse(conversion.Snapshot)
+	response["from"] = conversion.Amount.Currency()
+	response["to"] = conversion.Result.Currency()
+	response["amount"] = conversion.Amount.AmountString()
+	response["result"] = result
+	response["rate"] = conversion.Rate.String()
+	response["inverse_rate"] = conversion.InverseRate.String()
+
+	addFormatted(response, "formatted", conversion.Result, locale)
+
+	return response
+}
 
+// addFormatted adds the locale-formatted amount under key when a locale was
+// requested.
+func addFormatted(response gin.H, key string, amount money.Money, locale *language.Tag) {
+	if locale == nil {
 		return
 	}
-	c.JSON(http.StatusInternalServerError, gin.H{
-		"error":        http.StatusInternalServerError,
-		"errorMessage": "An unexpected error occured",
-	})
 
+	response[key] = amount.Format(*locale)
+	response["locale"] = locale.String()
 }
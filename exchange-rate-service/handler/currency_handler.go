@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/exchange-rate-service/service"
+)
+
+type CurrencyHandler struct {
+	rateFetcher *service.RateFetcherService
+}
+
+func NewCurrencyHandler(rateFetcher *service.RateFetcherService) *CurrencyHandler {
+	return &CurrencyHandler{
+		rateFetcher: rateFetcher,
+	}
+}
+
+func (h *CurrencyHandler) HandleCurrencies(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"currencies": h.rateFetcher.Currencies(),
+	})
+}
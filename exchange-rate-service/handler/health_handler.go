@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/exchange-rate-service/service"
+)
+
+type HealthHandler struct {
+	rateFetcher *service.RateFetcherService
+}
+
+func NewHealthHandler(rateFetcher *service.RateFetcherService) *HealthHandler {
+	return &HealthHandler{
+		rateFetcher: rateFetcher,
+	}
+}
+
+// HandleLiveness reports that the process is serving requests. It always
+// succeeds; the details are informational.
+func (h *HealthHandler) HandleLiveness(c *gin.Context) {
+	report := h.rateFetcher.Health()
+
+	response := healthResponse(report)
+	response["status"] = "ok"
+
+	c.JSON(http.StatusOK, response)
+}
+
+// HandleReadiness fails with 503 while the latest rates are missing or too
+// old, or no rate provider is healthy.
+func (h *HealthHandler) HandleReadiness(c *gin.Context) {
+	report := h.rateFetcher.Health()
+
+	response := healthResponse(report)
+	if !report.Ready {
+		response["status"] = "not_ready"
+		response["problems"] = report.Problems
+		c.JSON(http.StatusServiceUnavailable, response)
+		return
+	}
+
+	response["status"] = "ready"
+	c.JSON(http.StatusOK, response)
+}
+
+func healthResponse(report *service.HealthReport) gin.H {
+	cache := gin.H{
+		"last_updated":         formatTime(report.LastUpdated),
+		"age_seconds":          int64(report.CacheAge.Seconds()),
+		"max_rate_age_seconds": int64(report.MaxRateAge.Seconds()),
+	}
+
+	providers := make([]gin.H, 0, len(report.Providers))
+	for _, provider := range report.Providers {
+		providers = append(providers, gin.H{
+			"name":                 provider.Name,
+			"healthy":              provider.Healthy,
+			"last_success":         formatTime(provider.LastSuccess),
+			"last_failure":         formatTime(provider.LastFailure),
+			"last_error":           provider.LastError,
+			"consecutive_failures": provider.ConsecutiveFailures,
+		})
+	}
+
+	scheduler := gin.H{
+		"running":          report.Scheduler.Running,
+		"interval_seconds": int64(report.Scheduler.Interval.Seconds()),
+		"last_run":         formatTime(report.Scheduler.LastRun),
+		"last_error":       report.Scheduler.LastError,
+		"next_run":         formatTime(report.Scheduler.NextRun),
+	}
+
+	return gin.H{
+		"cache":     cache,
+		"providers": providers,
+		"scheduler": scheduler,
+	}
+}
+
+// formatTime renders t as RFC 3339, or "" for the zero time.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
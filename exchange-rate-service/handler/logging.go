@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// redactedParams are query parameters that carry credentials and are never
+// logged.
+var redactedParams = []string{apiKeyQuery, "token", "access_token"}
+
+// RequestLogger logs each request like gin's default logger, adding the
+// subject of the bearer token it was authenticated with. Credentials in the
+// query string are redacted.
+func RequestLogger() gin.HandlerFunc {
+	return gin.LoggerWithFormatter(formatRequestLog)
+}
+
+func formatRequestLog(param gin.LogFormatterParams) string {
+	subject := "-"
+	if value, ok := param.Keys[subjectContextKey].(string); ok {
+		subject = value
+	}
+
+	if param.Latency > time.Minute {
+		param.Latency = param.Latency.Truncate(time.Second)
+	}
+
+	return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-7s %#v | sub=%s\n%s",
+		param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+		param.StatusCode,
+		param.Latency,
+		param.ClientIP,
+		param.Method,
+		redactQuery(param.Path),
+		subject,
+		param.ErrorMessage,
+	)
+}
+
+// redactQuery replaces the values of redactedParams in path's query string,
+// keeping the other parameters as they were sent.
+func redactQuery(path string) string {
+	base, query, ok := strings.Cut(path, "?")
+	if !ok {
+		return path
+	}
+
+	params := strings.Split(query, "&")
+	for i, param := range params {
+		name, _, _ := strings.Cut(param, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil && contains(redactedParams, unescaped) {
+			params[i] = name + "=REDACTED"
+		}
+	}
+
+	return base + "?" + strings.Join(params, "&")
+}
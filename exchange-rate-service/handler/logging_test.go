@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestFormatRequestLogRedactsCredentials(t *testing.T) {
+	line := formatRequestLog(gin.LogFormatterParams{
+		TimeStamp:  time.Date(2025, 11, 28, 10, 0, 0, 0, time.UTC),
+		StatusCode: 200,
+		Method:     "GET",
+		Path:       "/v1/rates/ws?base=USD&api_key=erk_secret&token=abc&api%5Fkey=erk_other",
+		Keys:       map[any]any{subjectContextKey: "svc-ledger"},
+	})
+
+	for _, secret := range []string{"erk_secret", "abc", "erk_other"} {
+		if strings.Contains(line, secret) {
+			t.Errorf("Log line %q contains the credential %s", line, secret)
+		}
+	}
+	if !strings.Contains(line, "/v1/rates/ws?base=USD&api_key=REDACTED&token=REDACTED&api%5Fkey=REDACTED") {
+		t.Errorf("Log line %q, expected the other parameters kept", line)
+	}
+	if !strings.Contains(line, "sub=svc-ledger") {
+		t.Errorf("Log line %q, expected the token subject", line)
+	}
+
+	if got := redactQuery("/v1/rates"); got != "/v1/rates" {
+		t.Errorf("redactQuery without a query = %q", got)
+	}
+}
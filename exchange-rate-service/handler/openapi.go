@@ -0,0 +1,388 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+)
+
+// Schema is the subset of the OpenAPI schema object the API uses.
+type Schema struct {
+	Type        string             `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	Default     string             `json:"default,omitempty"`
+	Minimum     *int               `json:"minimum,omitempty"`
+	Maximum     *int               `json:"maximum,omitempty"`
+	MaxItems    *int               `json:"maxItems,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	OneOf       []*Schema          `json:"oneOf,omitempty"`
+	Ref         string             `json:"$ref,omitempty"`
+}
+
+// OpenAPIDocument is an OpenAPI 3 description of the API.
+type OpenAPIDocument struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       openAPIInfo                     `json:"info"`
+	Paths      map[string]map[string]operation `json:"paths"`
+	Components openAPIComponents               `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	Responses       map[string]response       `json:"responses"`
+	SecuritySchemes map[string]securityScheme `json:"securitySchemes"`
+}
+
+type securityScheme struct {
+	Type         string `json:"type"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+type operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary"`
+	Description string                `json:"description,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+	Parameters  []parameter           `json:"parameters,omitempty"`
+	RequestBody *requestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type requestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]mediaType `json:"content"`
+}
+
+type response struct {
+	Description string               `json:"description,omitempty"`
+	Content     map[string]mediaType `json:"content,omitempty"`
+	Ref         string               `json:"$ref,omitempty"`
+}
+
+type mediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// NewOpenAPIDocument describes routes under APIVersion, their deprecated
+// unversioned aliases, and every error code.
+func NewOpenAPIDocument(routes []Route) *OpenAPIDocument {
+	document := &OpenAPIDocument{
+		OpenAPI: "3.0.3",
+		Info: openAPIInfo{
+			Title:   "Exchange Rate Service",
+			Version: strings.TrimPrefix(APIVersion, "/"),
+			Description: "Currency conversion and exchange rates. Unversioned paths are " +
+				"deprecated aliases of the " + APIVersion + " paths.",
+		},
+		Paths: make(map[string]map[string]operation),
+		Components: openAPIComponents{
+			Schemas: map[string]*Schema{
+				"Error": errorSchema(),
+			},
+			Responses: errorResponses(),
+			SecuritySchemes: map[string]securityScheme{
+				"apiKeyHeader": {Type: "apiKey", In: "header", Name: apiKeyHeader, Description: "API key issued by POST /v1/keys"},
+				"apiKeyQuery":  {Type: "apiKey", In: "query", Name: apiKeyQuery, Description: "API key, for clients that cannot set headers"},
+				"bearerAuth":   {Type: "http", Scheme: "bearer", BearerFormat: "JWT", Description: "OIDC token whose roles grant the scope"},
+			},
+		},
+	}
+
+	for _, route := range routes {
+		path := openAPIPath(route.Path)
+		op := newOperation(route)
+		addOperation(document.Paths, APIVersion+path, route.Method, op)
+
+		op.Deprecated = true
+		op.OperationID += "Deprecated"
+		addOperation(document.Paths, path, route.Method, op)
+	}
+
+	for _, route := range serviceRoutes() {
+		addOperation(document.Paths, openAPIPath(route.Path), route.Method, newOperation(route))
+	}
+
+	return document
+}
+
+// serviceRoutes documents the endpoints served outside the route table,
+// at their full paths and without deprecated aliases: the probes and
+// metrics at the root, and this document and the error code pages under
+// APIVersion.
+func serviceRoutes() []Route {
+	return []Route{
+		{
+			Method:      http.MethodGet,
+			Path:        "/healthz",
+			OperationID: "getLiveness",
+			Summary:     "Liveness probe",
+			Description: "Succeeds while the process is serving requests.",
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/readyz",
+			OperationID: "getReadiness",
+			Summary:     "Readiness probe",
+			Description: "Fails with 503 while the latest rates are missing or too old, or no rate provider is healthy.",
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/metrics",
+			OperationID: "getMetrics",
+			Summary:     "Prometheus metrics",
+			Produces:    "text/plain; version=0.0.4",
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        APIVersion + "/openapi.json",
+			OperationID: "getOpenAPIDocument",
+			Summary:     "This OpenAPI document",
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        APIVersion + "/errors/:code",
+			OperationID: "describeErrorCode",
+			Summary:     "Describe an error code",
+			Description: "The target of the type of error responses.",
+			Params: []Param{
+				{Name: "code", In: "path", Type: "string", Description: "Error code", Required: true},
+			},
+		},
+	}
+}
+
+func addOperation(paths map[string]map[string]operation, path, method string, op operation) {
+	if paths[path] == nil {
+		paths[path] = make(map[string]operation)
+	}
+
+	paths[path][strings.ToLower(method)] = op
+}
+
+// openAPIPath rewrites gin path parameters such as :id to OpenAPI's {id}.
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func newOperation(route Route) operation {
+	produces, body := route.Produces, &Schema{Type: "string"}
+	if produces == "" {
+		produces, body = "application/json", &Schema{Type: "object"}
+	}
+
+	op := operation{
+		OperationID: route.OperationID,
+		Summary:     route.Summary,
+		Description: route.Description,
+		Responses: map[string]response{
+			"200": {
+				Description: "Success",
+				Content: map[string]mediaType{
+					produces: {Schema: body},
+				},
+			},
+		},
+	}
+
+	for _, param := range route.Params {
+		switch param.Name {
+		case "If-None-Match":
+			op.Responses["304"] = response{Description: "Not Modified: the cached response identified by If-None-Match or If-Modified-Since is current"}
+		case "format":
+			for _, format := range param.Enum {
+				op.Responses["200"].Content[Format(format).MediaType()] = mediaType{Schema: formatSchema(Format(format))}
+			}
+		}
+
+		in := param.In
+		if in == "" {
+			in = "query"
+		}
+
+		op.Parameters = append(op.Parameters, parameter{
+			Name:        param.Name,
+			In:          in,
+			Description: param.Description,
+			Required:    param.Required,
+			Schema: &Schema{
+				Type:    param.Type,
+				Format:  param.Format,
+				Enum:    param.Enum,
+				Default: param.Default,
+				Minimum: param.Minimum,
+				Maximum: param.Maximum,
+			},
+		})
+	}
+
+	// OpenAPI 3.0 only lists scopes for OAuth schemes, so the scope an
+	// API key or token needs is given in the description.
+	if route.Scope != "" {
+		op.Description = strings.TrimSpace(op.Description + " Requires an API key or bearer token with the " + string(route.Scope) + " scope.")
+		op.Security = []map[string][]string{
+			{"apiKeyHeader": {}},
+			{"apiKeyQuery": {}},
+			{"bearerAuth": {}},
+		}
+	}
+
+	if route.Body != nil {
+		op.RequestBody = &requestBody{
+			Required: true,
+			Content: map[string]mediaType{
+				"application/json": {Schema: route.Body},
+			},
+		}
+	}
+
+	for _, status := range errorStatuses() {
+		code := strconv.Itoa(status)
+		op.Responses[code] = response{Ref: "#/components/responses/" + code}
+	}
+
+	return op
+}
+
+// describeErrorCode serves the documentation of the error code in the
+// path.
+func describeErrorCode(c *gin.Context) {
+	code := c.Param("code")
+	info, ok := appErrors.Lookup(appErrors.ErrorCode(code))
+	if !ok {
+		respondWithError(c, appErrors.UnknownErrorCodeError(code))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"type":     problemType(info.Code),
+		"code":     info.Code,
+		"title":    info.Description,
+		"status":   info.Category.HTTPStatus(),
+		"category": info.Category,
+	})
+}
+
+// errorSchema describes the RFC 7807 problem body, listing every code with
+// its category.
+func errorSchema() *Schema {
+	codes := make([]string, 0, len(appErrors.Codes))
+	var description strings.Builder
+	description.WriteString("Error codes:\n")
+
+	for _, info := range appErrors.Codes {
+		codes = append(codes, string(info.Code))
+		fmt.Fprintf(&description, "\n- `%s` (%d %s): %s", info.Code, info.Category.HTTPStatus(), info.Category, info.Description)
+	}
+
+	return &Schema{
+		Type:     "object",
+		Required: []string{"type", "title", "status"},
+		Properties: map[string]*Schema{
+			"type":     {Type: "string", Format: "uri-reference", Description: "Documentation of the error code, or about:blank for unexpected errors"},
+			"title":    {Type: "string"},
+			"status":   {Type: "integer"},
+			"detail":   {Type: "string"},
+			"instance": {Type: "string", Format: "uri-reference", Description: "The request path"},
+			"code":     {Type: "string", Enum: codes, Description: description.String()},
+		},
+	}
+}
+
+// errorResponses defines one shared response per status that error codes
+// map to.
+func errorResponses() map[string]response {
+	responses := make(map[string]response)
+	for _, status := range errorStatuses() {
+		var codes []string
+		for _, info := range appErrors.Codes {
+			if info.Category.HTTPStatus() == status {
+				codes = append(codes, string(info.Code))
+			}
+		}
+
+		content := make(map[string]mediaType, len(AllFormats))
+		for _, format := range AllFormats {
+			schema := formatSchema(format)
+			switch format {
+			case FormatJSON:
+				schema = &Schema{Ref: "#/components/schemas/Error"}
+			case FormatXML:
+				schema = &Schema{Type: "string", Description: "XML problem document with a <problem> root element"}
+			}
+			content[format.problemMediaType()] = mediaType{Schema: schema}
+		}
+
+		responses[strconv.Itoa(status)] = response{
+			Description: fmt.Sprintf("%s: %s", http.StatusText(status), strings.Join(codes, ", ")),
+			Content:     content,
+		}
+	}
+
+	return responses
+}
+
+// formatSchema describes a non-JSON body: CSV with a header row, XML
+// rooted at <response>, or an exchangerate.v1 protobuf message.
+func formatSchema(format Format) *Schema {
+	switch format {
+	case FormatCSV:
+		return &Schema{Type: "string", Description: "CSV with a header row"}
+	case FormatXML:
+		return &Schema{Type: "string", Description: "XML document with a <response> root element"}
+	case FormatProtobuf:
+		return &Schema{Type: "string", Format: "binary", Description: "exchangerate.v1 protobuf message; errors are exchangerate.v1.Error"}
+	default:
+		return &Schema{Type: "object"}
+	}
+}
+
+// errorStatuses returns the distinct statuses of all error codes.
+func errorStatuses() []int {
+	seen := make(map[int]bool)
+	var statuses []int
+	for _, info := range appErrors.Codes {
+		status := info.Category.HTTPStatus()
+		if !seen[status] {
+			seen[status] = true
+			statuses = append(statuses, status)
+		}
+	}
+	sort.Ints(statuses)
+
+	return statuses
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+	"github.com/yourusername/exchange-rate-service/money"
+	"github.com/yourusername/exchange-rate-service/service"
+)
+
+type QuoteHandler struct {
+	rateFetcher *service.RateFetcherService
+}
+
+func NewQuoteHandler(rateFetcher *service.RateFetcherService) *QuoteHandler {
+	return &QuoteHandler{
+		rateFetcher: rateFetcher,
+	}
+}
+
+func (h *QuoteHandler) HandleQuote(c *gin.Context) {
+
+	from := c.Query("from")
+	to := c.Query("to")
+	amountStr := c.Query("amount")
+	targetStr := c.Query("target_amount")
+	dateStr := c.Query("date")
+
+	client := c.Query("client")
+	if client == "" {
+		client = c.GetHeader("X-Client-ID")
+	}
+
+	if from == "" {
+		respondWithError(c, appErrors.MissingParameterError("from"))
+		return
+	}
+
+	if to == "" {
+		respondWithError(c, appErrors.MissingParameterError("to"))
+		return
+	}
+
+	if amountStr != "" && targetStr != "" {
+		respondWithError(c, appErrors.ConflictingParametersError("amount", "target_amount"))
+		return
+	}
+
+	if amountStr == "" && targetStr == "" {
+		respondWithError(c, appErrors.MissingParameterError("amount"))
+		return
+	}
+
+	date, err := parseDate(dateStr)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	var quote *service.Quote
+	if targetStr != "" {
+		var target money.Money
+		if target, err = money.Parse(targetStr, to); err == nil {
+			quote, err = h.rateFetcher.QuoteReverse(client, from, target, date)
+		}
+	} else {
+		var amount money.Money
+		if amount, err = money.Parse(amountStr, from); err == nil {
+			quote, err = h.rateFetcher.Quote(client, amount, to, date)
+		}
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	render(c, http.StatusOK, Body{Fields: gin.H{
+		"from":       quote.Amount.Currency(),
+		"to":         quote.NetAmount.Currency(),
+		"amount":     quote.Amount.AmountString(),
+		"mid_rate":   quote.MidRate.String(),
+		"bid_rate":   quote.BidRate.String(),
+		"ask_rate":   quote.AskRate.String(),
+		"fee":        quote.Fee.AmountString(),
+		"net_amount": quote.NetAmount.AmountString(),
+	}})
+}
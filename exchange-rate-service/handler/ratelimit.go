@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+	"github.com/yourusername/exchange-rate-service/service"
+)
+
+// dateRange is an inclusive range of days a request needs rates for.
+type dateRange struct {
+	start, end time.Time
+}
+
+// Limiter rate limits each client, identified by its API key or token
+// subject, or else its IP address. Requests that will fetch historical
+// rates from the provider are charged to a separate, smaller bucket, one
+// token per uncached day.
+type Limiter struct {
+	limiter     *service.RateLimiter
+	rateFetcher *service.RateFetcherService
+}
+
+func NewLimiter(limiter *service.RateLimiter, rateFetcher *service.RateFetcherService) *Limiter {
+	return &Limiter{
+		limiter:     limiter,
+		rateFetcher: rateFetcher,
+	}
+}
+
+// Limit charges requests to route and rejects them with 429 once their
+// bucket is empty, or with 400 when they need more upstream tokens than
+// the bucket holds. Responses carry RateLimit-* headers for the bucket charged. When
+// the store fails the request is let through.
+func (l *Limiter) Limit(route Route) gin.HandlerFunc {
+	dates := route.dates
+	if dates == nil {
+		dates = queryDates
+	}
+
+	return func(c *gin.Context) {
+		fetches := 0
+		for _, days := range dates(c) {
+			fetches += l.rateFetcher.UncachedDays(days.start, days.end)
+		}
+
+		result, err := l.limiter.Allow(c.Request.Context(), clientIdentity(c), fetches)
+		if errors.Is(err, &appErrors.CustomError{Code: appErrors.ErrTooManyFetches}) {
+			respondWithError(c, err)
+			c.Abort()
+			return
+		}
+		if err != nil {
+			log.Printf("Rate limit check failed, allowing request: %v", err)
+			c.Next()
+			return
+		}
+
+		if result == nil {
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Policy", result.Limit.String())
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit.Burst))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(max(1, ceilSeconds(result.RetryAfter))))
+			respondWithError(c, appErrors.RateLimitedError(result.Bucket))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// clientIdentity names the bucket owner: the API key or token subject the
+// request was authenticated with, or else the client IP.
+func clientIdentity(c *gin.Context) string {
+	if key, ok := requestAPIKey(c); ok {
+		return "key:" + key.ID
+	}
+	if principal, ok := requestPrincipal(c); ok {
+		return "sub:" + principal.Subject
+	}
+
+	return "ip:" + c.ClientIP()
+}
+
+// queryDates reads the date parameter and the start_date to end_date
+// range. Invalid dates are left to parameter validation.
+func queryDates(c *gin.Context) []dateRange {
+	var ranges []dateRange
+
+	if date, err := parseDate(c.Query("date")); err == nil && date != nil {
+		ranges = append(ranges, dateRange{*date, *date})
+	}
+
+	start, startErr := parseDate(c.Query("start_date"))
+	end, endErr := parseDate(c.Query("end_date"))
+	if startErr == nil && endErr == nil && start != nil && end != nil {
+		ranges = append(ranges, dateRange{*start, *end})
+	}
+
+	return ranges
+}
+
+// batchDates reads the distinct item dates of a batch body. Invalid bodies
+// are left to body validation.
+func batchDates(c *gin.Context) []dateRange {
+	items, err := batchItems(c)
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var ranges []dateRange
+	for _, item := range items {
+		date, err := parseDate(item.Date)
+		if err != nil || date == nil || seen[item.Date] {
+			continue
+		}
+		seen[item.Date] = true
+		ranges = append(ranges, dateRange{*date, *date})
+	}
+
+	return ranges
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/exchange-rate-service/service"
+)
+
+func TestLimiterLimit(t *testing.T) {
+	t.Setenv("RATE_LIMIT_CACHED", "2/1m")
+	t.Setenv("RATE_LIMIT_UPSTREAM", "1/1h")
+	t.Setenv("RATE_LIMIT_REDIS_URL", "")
+	t.Setenv("API_KEY", "test")
+	limiter := NewLimiter(service.NewRateLimiter(), service.NewRateFetcherService())
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+	r.GET("/rates", limiter.Limit(Route{}), ok)
+	r.POST("/batch", readBody(), limiter.Limit(Route{dates: batchDates}), func(c *gin.Context) {
+		body, _ := c.GetRawData()
+		c.String(http.StatusOK, "%s", body)
+	})
+
+	yesterday := time.Now().UTC().AddDate(0, 0, -1).Format("2006-01-02")
+
+	tests := []struct {
+		url       string
+		client    string
+		status    int
+		remaining string
+	}{
+		{"/rates", "1.1.1.1", http.StatusNoContent, "1"},
+		{"/rates", "1.1.1.1", http.StatusNoContent, "0"},
+		{"/rates", "1.1.1.1", http.StatusTooManyRequests, "0"},
+		{"/rates", "2.2.2.2", http.StatusNoContent, "1"},
+		{"/rates?date=" + yesterday, "2.2.2.2", http.StatusNoContent, "0"},
+		{"/rates?date=" + yesterday, "2.2.2.2", http.StatusTooManyRequests, "0"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+		req.RemoteAddr = tt.client + ":1234"
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s from %s: status %d, expected %d", tt.url, tt.client, w.Code, tt.status)
+		}
+		if remaining := w.Header().Get("RateLimit-Remaining"); remaining != tt.remaining {
+			t.Errorf("%s from %s: RateLimit-Remaining %q, expected %q", tt.url, tt.client, remaining, tt.remaining)
+		}
+		if w.Header().Get("RateLimit-Policy") == "" || w.Header().Get("RateLimit-Reset") == "" {
+			t.Errorf("%s from %s: expected RateLimit-Policy and RateLimit-Reset headers", tt.url, tt.client)
+		}
+
+		if tt.status == http.StatusTooManyRequests {
+			if w.Header().Get("Retry-After") == "" {
+				t.Errorf("%s from %s: expected Retry-After", tt.url, tt.client)
+			}
+			if !strings.Contains(w.Body.String(), "RATE_LIMITED") {
+				t.Errorf("%s from %s: body %s, expected RATE_LIMITED", tt.url, tt.client, w.Body.String())
+			}
+		}
+	}
+
+	body := `[{"from":"USD","to":"EUR","amount":1,"date":"` + yesterday + `"}]`
+	req := httptest.NewRequest(http.MethodPost, "/batch", strings.NewReader(body))
+	req.RemoteAddr = "3.3.3.3:1234"
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || w.Body.String() != body {
+		t.Errorf("Batch: status %d body %s, expected the body passed on", w.Code, w.Body.String())
+	}
+	if policy := w.Header().Get("RateLimit-Policy"); policy != "1;w=3600" {
+		t.Errorf("Batch: RateLimit-Policy %q, expected the upstream bucket", policy)
+	}
+
+	dayBefore := time.Now().UTC().AddDate(0, 0, -2).Format("2006-01-02")
+	body = `[{"from":"USD","to":"EUR","amount":1,"date":"` + yesterday + `"},{"from":"USD","to":"EUR","amount":1,"date":"` + dayBefore + `"}]`
+	req = httptest.NewRequest(http.MethodPost, "/batch", strings.NewReader(body))
+	req.RemoteAddr = "4.4.4.4:1234"
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "TOO_MANY_FETCHES") || !strings.Contains(w.Body.String(), "maximum of 1") {
+		t.Errorf("Batch over the upstream burst: status %d body %s, expected TOO_MANY_FETCHES", w.Code, w.Body.String())
+	}
+	if w.Header().Get("Retry-After") != "" {
+		t.Errorf("Batch over the upstream burst: Retry-After %q, expected none", w.Header().Get("Retry-After"))
+	}
+}
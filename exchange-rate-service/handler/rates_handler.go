@@ -0,0 +1,322 @@
+package handler
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+	"github.com/yourusername/exchange-rate-service/money"
+	"github.com/yourusername/exchange-rate-service/protoconv"
+	"github.com/yourusername/exchange-rate-service/service"
+)
+
+const defaultRatePrecision = 6
+
+type RatesHandler struct {
+	rateFetcher *service.RateFetcherService
+}
+
+func NewRatesHandler(rateFetcher *service.RateFetcherService) *RatesHandler {
+	return &RatesHandler{
+		rateFetcher: rateFetcher,
+	}
+}
+
+func (h *RatesHandler) HandleRates(c *gin.Context) {
+	base := c.DefaultQuery("base", "USD")
+	symbols := parseSymbols(c.Query("symbols"))
+
+	date, err := parseDate(c.Query("date"))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	rounding, err := parseRounding(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	table, err := h.rateFetcher.GetRates(base, symbols, date)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	if notModified(c, h.rateFetcher.Freshness(table.Snapshot)) {
+		return
+	}
+
+	response := rateTableResponse(table, rounding)
+	rates := response["rates"].(map[string]string)
+
+	message := protoconv.RateTable(table)
+	message.Rates = rates
+
+	header := []string{"base", "symbol", "rate", "timestamp"}
+	if table.Snapshot.Date != nil {
+		header = append(header, "date")
+	}
+	rows := [][]string{header}
+	for _, symbol := range sortedKeys(rates) {
+		row := []string{table.Base, symbol, rates[symbol], response["timestamp"].(string)}
+		if table.Snapshot.Date != nil {
+			row = append(row, response["date"].(string))
+		}
+		rows = append(rows, row)
+	}
+
+	render(c, http.StatusOK, Body{Fields: response, Rows: rows, Message: message})
+}
+
+// rateTableResponse builds the rate table body shared by /rates and
+// /rates/stream.
+func rateTableResponse(table *service.RateTable, rounding money.Rounding) gin.H {
+	rates := make(map[string]string, len(table.Rates))
+	for symbol, rate := range table.Rates {
+		rates[symbol] = formatRate(rate, rounding)
+	}
+
+	response := snapshotResponse(table.Snapshot)
+	response["base"] = table.Base
+	response["rates"] = rates
+
+	return response
+}
+
+// HandleMatrix returns every supported currency against every other.
+func (h *RatesHandler) HandleMatrix(c *gin.Context) {
+	date, err := parseDate(c.Query("date"))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	rounding, err := parseRounding(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	matrix, err := h.rateFetcher.GetMatrix(date)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	if notModified(c, h.rateFetcher.Freshness(matrix.Snapshot)) {
+		return
+	}
+
+	cells := make(map[string]map[string]string, len(matrix.Rates))
+	for from, row := range matrix.Rates {
+		cells[from] = make(map[string]string, len(row))
+		for to, rate := range row {
+			cells[from][to] = formatRate(rate, rounding)
+		}
+	}
+
+	response := snapshotResponse(matrix.Snapshot)
+	response["currencies"] = matrix.Currencies
+	response["matrix"] = cells
+
+	rows := [][]string{{"from", "to", "rate"}}
+	for _, from := range sortedKeys(cells) {
+		for _, to := range sortedKeys(cells[from]) {
+			rows = append(rows, []string{from, to, cells[from][to]})
+		}
+	}
+
+	render(c, http.StatusOK, Body{Fields: response, Rows: rows})
+}
+
+// HandleTimeseries returns daily rate tables for a date range.
+func (h *RatesHandler) HandleTimeseries(c *gin.Context) {
+	base := c.DefaultQuery("base", "USD")
+	symbols := parseSymbols(c.Query("symbols"))
+
+	start, end, err := parseDateRange(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	rounding, err := parseRounding(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	series, err := h.rateFetcher.GetTimeseries(base, symbols, *start, *end)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	if notModified(c, h.rateFetcher.Freshness(series.Snapshots...)) {
+		return
+	}
+
+	rates := make(map[string]map[string]string, len(series.Rates))
+	for date, table := range series.Rates {
+		rates[date] = make(map[string]string, len(table))
+		for symbol, rate := range table {
+			rates[date][symbol] = formatRate(rate, rounding)
+		}
+	}
+
+	rows := [][]string{{"date", "base", "symbol", "rate"}}
+	for _, date := range sortedKeys(rates) {
+		for _, symbol := range sortedKeys(rates[date]) {
+			rows = append(rows, []string{date, series.Base, symbol, rates[date][symbol]})
+		}
+	}
+
+	render(c, http.StatusOK, Body{
+		Fields: gin.H{
+			"base":       series.Base,
+			"start_date": series.StartDate.Format("2006-01-02"),
+			"end_date":   series.EndDate.Format("2006-01-02"),
+			"provider":   series.Provider,
+			"rates":      rates,
+		},
+		Rows: rows,
+	})
+}
+
+// HandleFluctuation returns how each symbol moved between two dates.
+func (h *RatesHandler) HandleFluctuation(c *gin.Context) {
+	base := c.DefaultQuery("base", "USD")
+	symbols := parseSymbols(c.Query("symbols"))
+
+	start, end, err := parseDateRange(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	rounding, err := parseRounding(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	fluctuation, err := h.rateFetcher.GetFluctuation(base, symbols, *start, *end)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	if notModified(c, h.rateFetcher.Freshness(fluctuation.Snapshots...)) {
+		return
+	}
+
+	startDate := fluctuation.StartDate.Format("2006-01-02")
+	endDate := fluctuation.EndDate.Format("2006-01-02")
+
+	rates := make(map[string]gin.H, len(fluctuation.Rates))
+	rows := [][]string{{"base", "symbol", "start_date", "end_date", "start_rate", "end_rate", "change", "change_pct"}}
+	for _, symbol := range sortedKeys(fluctuation.Rates) {
+		change := fluctuation.Rates[symbol]
+		startRate, endRate := formatRate(change.StartRate, rounding), formatRate(change.EndRate, rounding)
+		delta, deltaPct := formatRate(change.Change, rounding), formatRate(change.ChangePct, rounding)
+
+		rates[symbol] = gin.H{
+			"start_rate": startRate,
+			"end_rate":   endRate,
+			"change":     delta,
+			"change_pct": deltaPct,
+		}
+		rows = append(rows, []string{fluctuation.Base, symbol, startDate, endDate, startRate, endRate, delta, deltaPct})
+	}
+
+	render(c, http.StatusOK, Body{
+		Fields: gin.H{
+			"base":       fluctuation.Base,
+			"start_date": startDate,
+			"end_date":   endDate,
+			"provider":   fluctuation.Provider,
+			"rates":      rates,
+		},
+		Rows: rows,
+	})
+}
+
+// parseDateRange reads the required start_date and end_date parameters.
+func parseDateRange(c *gin.Context) (*time.Time, *time.Time, error) {
+	startStr := c.Query("start_date")
+	endStr := c.Query("end_date")
+
+	if startStr == "" {
+		return nil, nil, appErrors.MissingParameterError("start_date")
+	}
+	if endStr == "" {
+		return nil, nil, appErrors.MissingParameterError("end_date")
+	}
+
+	start, err := parseDate(startStr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	end, err := parseDate(endStr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return start, end, nil
+}
+
+// formatRate renders a rate at full precision unless the request asked for
+// rounding, in which case it defaults to defaultRatePrecision places.
+func formatRate(rate decimal.Decimal, rounding money.Rounding) string {
+	if rounding.Mode == "" && rounding.Places == nil {
+		return rate.String()
+	}
+
+	return rounding.Apply(rate, defaultRatePrecision).String()
+}
+
+// parseSymbols splits a comma-separated symbols parameter, dropping blanks.
+func parseSymbols(value string) []string {
+	var symbols []string
+	for _, symbol := range strings.Split(value, ",") {
+		if symbol = strings.TrimSpace(symbol); symbol != "" {
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	return symbols
+}
+
+// sortedKeys returns the keys of m in order, for stable CSV rows.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// snapshotResponse describes where and when a rate table came from.
+func snapshotResponse(snapshot *service.RateSnapshot) gin.H {
+	response := gin.H{
+		"timestamp":         snapshot.UpdatedAt.UTC().Format(time.RFC3339),
+		"provider":          snapshot.Provider,
+		"cache_age_seconds": int64(snapshot.Age().Seconds()),
+		"stale":             snapshot.Stale,
+	}
+
+	if snapshot.Date != nil {
+		response["date"] = snapshot.Date.Format("2006-01-02")
+	}
+
+	return response
+}
@@ -0,0 +1,329 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+	"github.com/yourusername/exchange-rate-service/protoconv"
+	"google.golang.org/protobuf/proto"
+)
+
+// Format is a response encoding, chosen with the format parameter or the
+// Accept header.
+type Format string
+
+const (
+	FormatJSON     Format = "json"
+	FormatCSV      Format = "csv"
+	FormatXML      Format = "xml"
+	FormatProtobuf Format = "protobuf"
+)
+
+// AllFormats lists the formats in the order they are documented.
+var AllFormats = []Format{FormatJSON, FormatCSV, FormatXML, FormatProtobuf}
+
+// MediaType returns the Content-Type format is served with.
+func (f Format) MediaType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv"
+	case FormatXML:
+		return "application/xml"
+	case FormatProtobuf:
+		return "application/x-protobuf"
+	default:
+		return "application/json"
+	}
+}
+
+// problemMediaType returns the Content-Type errors in format are served
+// with: the RFC 7807 problem types for JSON and XML, otherwise MediaType.
+func (f Format) problemMediaType() string {
+	switch f {
+	case FormatJSON:
+		return "application/problem+json"
+	case FormatXML:
+		return "application/problem+xml"
+	default:
+		return f.MediaType()
+	}
+}
+
+// acceptedTypes maps the media types understood in Accept to formats.
+var acceptedTypes = map[string]Format{
+	"application/json":         FormatJSON,
+	"application/problem+json": FormatJSON,
+	"text/csv":                 FormatCSV,
+	"application/xml":          FormatXML,
+	"application/problem+xml":  FormatXML,
+	"text/xml":                 FormatXML,
+	"application/x-protobuf":   FormatProtobuf,
+	"application/protobuf":     FormatProtobuf,
+}
+
+const formatKey = "responseFormat"
+
+// Body is a response that can be rendered in every format. JSON and XML
+// encode Fields. CSV encodes Rows, a header followed by records, or Fields
+// flattened into a single record when Rows is empty. Protobuf encodes
+// Message; a body without one is not available as protobuf. A Problem body
+// is an RFC 7807 problem, served as application/problem+json or as
+// application/problem+xml rooted at <problem>.
+type Body struct {
+	Fields  gin.H
+	Rows    [][]string
+	Message proto.Message
+	Problem bool
+}
+
+// problemNamespace is the XML namespace of RFC 7807 problem documents.
+const problemNamespace = "urn:ietf:rfc:7807"
+
+// render writes body with status in the format the request asked for.
+func render(c *gin.Context, status int, body Body) {
+	format := requestFormat(c)
+
+	var data []byte
+	var err error
+	switch format {
+	case FormatCSV:
+		rows := body.Rows
+		if len(rows) == 0 {
+			rows = flattenRows(body.Fields)
+		}
+		data, err = encodeCSV(rows)
+	case FormatXML:
+		root := xml.StartElement{Name: xml.Name{Local: "response"}}
+		if body.Problem {
+			root = xml.StartElement{Name: xml.Name{Space: problemNamespace, Local: "problem"}}
+		}
+		data, err = encodeXML(root, body.Fields)
+	case FormatProtobuf:
+		if body.Message == nil {
+			respondWithError(c, appErrors.UnsupportedFormatError(string(format)))
+			return
+		}
+		data, err = proto.Marshal(body.Message)
+	default:
+		data, err = json.Marshal(body.Fields)
+	}
+
+	if err != nil {
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	c.Writer.Header().Add("Vary", "Accept")
+
+	contentType := format.MediaType()
+	if body.Problem {
+		contentType = format.problemMediaType()
+	}
+	if format != FormatProtobuf {
+		contentType += "; charset=utf-8"
+	}
+	c.Data(status, contentType, data)
+}
+
+// errorBody is err in every format, with the same code and message.
+func errorBody(err error) (int, Body) {
+	status, fields := errorResponse(err)
+	return status, Body{Fields: fields, Message: protoconv.Error(err), Problem: true}
+}
+
+// requestFormat returns the format named by the format parameter, or else
+// the most preferred supported type in Accept. It defaults to JSON.
+func requestFormat(c *gin.Context) Format {
+	if format, ok := c.Get(formatKey); ok {
+		return format.(Format)
+	}
+
+	format := negotiateFormat(c.Query("format"), c.GetHeader("Accept"))
+	c.Set(formatKey, format)
+
+	return format
+}
+
+func negotiateFormat(param, accept string) Format {
+	for _, format := range AllFormats {
+		if param == string(format) {
+			return format
+		}
+	}
+
+	best, bestQuality := FormatJSON, 0.0
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+
+		format, ok := acceptedTypes[mediaType]
+		if !ok {
+			continue
+		}
+
+		quality := 1.0
+		if q, err := strconv.ParseFloat(params["q"], 64); err == nil {
+			quality = q
+		}
+		if quality > bestQuality {
+			best, bestQuality = format, quality
+		}
+	}
+
+	return best
+}
+
+func encodeCSV(rows [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// flattenRows turns fields into a header of dotted keys and one record.
+func flattenRows(fields gin.H) [][]string {
+	values := make(map[string]string)
+	flatten("", fields, values)
+
+	header := make([]string, 0, len(values))
+	for key := range values {
+		header = append(header, key)
+	}
+	sort.Strings(header)
+
+	record := make([]string, len(header))
+	for i, key := range header {
+		record[i] = values[key]
+	}
+
+	return [][]string{header, record}
+}
+
+func flatten(prefix string, value any, values map[string]string) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			flatten(joinKey(prefix, fmt.Sprint(key.Interface())), v.MapIndex(key).Interface(), values)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			flatten(joinKey(prefix, strconv.Itoa(i)), v.Index(i).Interface(), values)
+		}
+	case reflect.Invalid:
+		values[prefix] = ""
+	default:
+		values[prefix] = fmt.Sprint(value)
+	}
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return prefix + "." + key
+}
+
+// encodeXML encodes fields as the root element. Map keys become child
+// elements, or <entry key="..."> when they are not valid element names,
+// such as dates; slice elements become <item> elements.
+func encodeXML(root xml.StartElement, fields gin.H) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+
+	enc := xml.NewEncoder(&buf)
+	if err := encodeXMLElement(enc, root, fields); err != nil {
+		return nil, err
+	}
+	if err := enc.Flush(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func encodeXMLElement(enc *xml.Encoder, start xml.StartElement, value any) error {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		children := make(map[string]any, v.Len())
+		for _, key := range v.MapKeys() {
+			name := fmt.Sprint(key.Interface())
+			keys = append(keys, name)
+			children[name] = v.MapIndex(key).Interface()
+		}
+		sort.Strings(keys)
+
+		if err := enc.EncodeToken(start); err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if err := encodeXMLElement(enc, xmlChild(key), children[key]); err != nil {
+				return err
+			}
+		}
+		return enc.EncodeToken(start.End())
+	case reflect.Slice, reflect.Array:
+		if err := enc.EncodeToken(start); err != nil {
+			return err
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := encodeXMLElement(enc, xml.StartElement{Name: xml.Name{Local: "item"}}, v.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return enc.EncodeToken(start.End())
+	case reflect.Invalid:
+		return enc.EncodeElement("", start)
+	default:
+		return enc.EncodeElement(fmt.Sprint(value), start)
+	}
+}
+
+func xmlChild(key string) xml.StartElement {
+	if isXMLName(key) {
+		return xml.StartElement{Name: xml.Name{Local: key}}
+	}
+
+	return xml.StartElement{
+		Name: xml.Name{Local: "entry"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: key}},
+	}
+}
+
+func isXMLName(name string) bool {
+	if name == "" || strings.HasPrefix(strings.ToLower(name), "xml") {
+		return false
+	}
+
+	for i, r := range name {
+		if r == '_' || unicode.IsLetter(r) {
+			continue
+		}
+		if i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.') {
+			continue
+		}
+		return false
+	}
+
+	return true
+}
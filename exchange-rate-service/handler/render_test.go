@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+	pb "github.com/yourusername/exchange-rate-service/proto/exchangerate/v1"
+	"github.com/yourusername/exchange-rate-service/service"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	tests := []struct {
+		param    string
+		accept   string
+		expected Format
+	}{
+		{"", "", FormatJSON},
+		{"", "*/*", FormatJSON},
+		{"", "text/csv", FormatCSV},
+		{"", "text/html, application/xml;q=0.9, */*;q=0.8", FormatXML},
+		{"", "application/json;q=0.5, application/x-protobuf", FormatProtobuf},
+		{"", "text/csv;q=0", FormatJSON},
+		{"csv", "application/xml", FormatCSV},
+		{"yaml", "text/xml", FormatXML},
+	}
+
+	for _, tt := range tests {
+		if format := negotiateFormat(tt.param, tt.accept); format != tt.expected {
+			t.Errorf("negotiateFormat(%q, %q) = %s, expected %s", tt.param, tt.accept, format, tt.expected)
+		}
+	}
+}
+
+func renderTestBody(t *testing.T, url, accept string, write func(c *gin.Context)) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, url, nil)
+	if accept != "" {
+		c.Request.Header.Set("Accept", accept)
+	}
+
+	write(c)
+	return w
+}
+
+func TestRenderFormats(t *testing.T) {
+	body := Body{
+		Fields: gin.H{"base": "USD", "rates": map[string]string{"EUR": "0.92"}, "timeseries": map[string]string{"2025-11-28": "x"}},
+		Rows:   [][]string{{"base", "symbol", "rate"}, {"USD", "EUR", "0.92"}},
+		Message: &pb.RateTable{
+			Base:  "USD",
+			Rates: map[string]string{"EUR": "0.92"},
+		},
+	}
+
+	tests := []struct {
+		url         string
+		accept      string
+		contentType string
+		contains    string
+	}{
+		{"/v1/rates", "", "application/json; charset=utf-8", `"rates":{"EUR":"0.92"}`},
+		{"/v1/rates?format=csv", "", "text/csv; charset=utf-8", "base,symbol,rate\nUSD,EUR,0.92\n"},
+		{"/v1/rates", "application/xml", "application/xml; charset=utf-8", `<rates><EUR>0.92</EUR></rates><timeseries><entry key="2025-11-28">x</entry></timeseries>`},
+	}
+
+	for _, tt := range tests {
+		w := renderTestBody(t, tt.url, tt.accept, func(c *gin.Context) { render(c, http.StatusOK, body) })
+
+		if w.Header().Get("Content-Type") != tt.contentType {
+			t.Errorf("%s %s: Content-Type %s, expected %s", tt.url, tt.accept, w.Header().Get("Content-Type"), tt.contentType)
+		}
+		if !strings.Contains(w.Body.String(), tt.contains) {
+			t.Errorf("%s %s: body %s, expected it to contain %s", tt.url, tt.accept, w.Body.String(), tt.contains)
+		}
+	}
+
+	w := renderTestBody(t, "/v1/rates?format=protobuf", "", func(c *gin.Context) { render(c, http.StatusOK, body) })
+	var table pb.RateTable
+	if err := proto.Unmarshal(w.Body.Bytes(), &table); err != nil || table.Rates["EUR"] != "0.92" {
+		t.Errorf("Protobuf body did not decode to the rate table: %v", err)
+	}
+
+	w = renderTestBody(t, "/v1/rates/matrix", "application/x-protobuf", func(c *gin.Context) {
+		render(c, http.StatusOK, Body{Fields: body.Fields})
+	})
+	var pbErr pb.Error
+	if w.Code != http.StatusNotAcceptable || proto.Unmarshal(w.Body.Bytes(), &pbErr) != nil || pbErr.Code != string(appErrors.ErrUnsupportedFormat) {
+		t.Errorf("Protobuf without a message: status %d, error %v", w.Code, &pbErr)
+	}
+}
+
+func TestHandleBatchRendersEachFormat(t *testing.T) {
+	t.Setenv("API_KEY", "test")
+	t.Setenv("SUPPORTED_CURRENCIES", "USD,EUR")
+	h := NewConvertHandler(service.NewRateFetcherService())
+	// Neither item needs rates: one names an unsupported currency and the
+	// other has no amount.
+	body := `[{"from":"USD","to":"XYZ","amount":"10"},{"from":"USD","to":"EUR"}]`
+
+	batch := func(url string) *httptest.ResponseRecorder {
+		return renderTestBody(t, url, "", func(c *gin.Context) {
+			c.Request = httptest.NewRequest(http.MethodPost, url, strings.NewReader(body))
+			readBody()(c)
+			h.HandleBatch(c)
+		})
+	}
+
+	w := batch("/v1/convert/batch?format=csv")
+	if !strings.HasPrefix(w.Body.String(), "index,from,to,amount,result,rate,code,detail\n0,USD,XYZ,10,,,UNSUPPORTED_CURRENCY,") {
+		t.Errorf("CSV body %s", w.Body.String())
+	}
+	if lines := strings.Count(w.Body.String(), "\n"); lines != 3 {
+		t.Errorf("CSV body has %d records, expected a header and one per item", lines)
+	}
+
+	w = batch("/v1/convert/batch?format=protobuf")
+	var response pb.BatchConvertResponse
+	if err := proto.Unmarshal(w.Body.Bytes(), &response); err != nil || len(response.Results) != 2 {
+		t.Fatalf("Protobuf body %v: %v", &response, err)
+	}
+	if code := response.Results[0].GetError().GetCode(); code != string(appErrors.ErrUnsupportedCurrency) {
+		t.Errorf("First result code %s, expected %s", code, appErrors.ErrUnsupportedCurrency)
+	}
+}
+
+func TestRenderErrorsInEachFormat(t *testing.T) {
+	err := appErrors.UnsupportedCurrencyError("XYZ")
+
+	tests := []struct {
+		format      string
+		contentType string
+		expected    string
+	}{
+		{"json", "application/problem+json", `{"code":"UNSUPPORTED_CURRENCY","detail":"unsupported currency: XYZ","instance":"/v1/convert","status":400,"title":"A currency is not configured or not quoted by the provider","type":"/v1/errors/UNSUPPORTED_CURRENCY"}`},
+		{"csv", "text/csv", "code,detail,instance,status,title,type\nUNSUPPORTED_CURRENCY,unsupported currency: XYZ,/v1/convert,400,"},
+		{"xml", "application/problem+xml", `<problem xmlns="urn:ietf:rfc:7807"><code>UNSUPPORTED_CURRENCY</code><detail>unsupported currency: XYZ</detail><instance>/v1/convert</instance><status>400</status>`},
+	}
+
+	for _, tt := range tests {
+		w := renderTestBody(t, "/v1/convert?format="+tt.format, "", func(c *gin.Context) { respondWithError(c, err) })
+
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), tt.expected) {
+			t.Errorf("%s: status %d, body %s", tt.format, w.Code, w.Body.String())
+		}
+		if contentType := w.Header().Get("Content-Type"); !strings.HasPrefix(contentType, tt.contentType) {
+			t.Errorf("%s: Content-Type %s, expected %s", tt.format, contentType, tt.contentType)
+		}
+	}
+
+	w := renderTestBody(t, "/v1/convert", "application/x-protobuf", func(c *gin.Context) { respondWithError(c, err) })
+	var pbErr pb.Error
+	if err := proto.Unmarshal(w.Body.Bytes(), &pbErr); err != nil || pbErr.Code != "UNSUPPORTED_CURRENCY" {
+		t.Errorf("Protobuf error body %v: %v", &pbErr, err)
+	}
+}
+
+func TestErrorResponseStatuses(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   any
+	}{
+		{appErrors.QuotaExceededError("daily limit of 1000 requests"), http.StatusTooManyRequests, appErrors.ErrQuotaExceeded},
+		{appErrors.RatesUnavailableError(appErrors.APIFetchError(nil)), http.StatusServiceUnavailable, appErrors.ErrRatesUnavailable},
+		{appErrors.UpstreamTimeoutError(nil), http.StatusGatewayTimeout, appErrors.ErrUpstreamTimeout},
+		{fmt.Errorf("converting: %w", appErrors.MissingRateError("EUR")), http.StatusInternalServerError, appErrors.ErrMissingRate},
+		{errors.New("boom"), http.StatusInternalServerError, nil},
+	}
+
+	for _, tt := range tests {
+		status, body := errorResponse(tt.err)
+		if status != tt.status || body["status"] != tt.status || body["code"] != tt.code {
+			t.Errorf("%v: status %d, body %v, expected %d %v", tt.err, status, body, tt.status, tt.code)
+		}
+	}
+
+	_, body := errorResponse(errors.New("boom"))
+	if body["type"] != "about:blank" || body["title"] != "Internal Server Error" {
+		t.Errorf("Unexpected error body %v", body)
+	}
+}
@@ -0,0 +1,600 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+	"github.com/yourusername/exchange-rate-service/money"
+	"github.com/yourusername/exchange-rate-service/service"
+)
+
+// APIVersion is the path prefix of the current API.
+const APIVersion = "/v1"
+
+// Param describes a request parameter. The same definition drives request
+// validation and the OpenAPI document.
+type Param struct {
+	Name        string
+	In          string // "query" (default), "header" or "path"
+	Description string
+	Type        string // "string", "number", "integer" or "boolean"
+	Format      string // e.g. "date"
+	Enum        []string
+	Minimum     *int
+	Maximum     *int
+	Default     string
+	Required    bool
+
+	// invalid builds the error for a value that does not match the schema.
+	// It defaults to InvalidParameterError.
+	invalid func(value string) error
+}
+
+// Route is an API endpoint with its documentation.
+type Route struct {
+	Method      string
+	Path        string
+	OperationID string
+	Summary     string
+	Description string
+	Params      []Param
+	Body        *Schema
+	Produces    string        // response media type, application/json by default
+	Scope       service.Scope // API key scope required; empty for public routes
+	Handle      gin.HandlerFunc
+
+	// dates returns the historical days the request needs rates for, so the
+	// rate limiter can charge provider fetches. It defaults to queryDates.
+	dates func(c *gin.Context) []dateRange
+	// itemErrors marks an array Body whose handler reports invalid items
+	// one by one, so only the array itself is validated up front.
+	itemErrors bool
+}
+
+// Handlers groups the handlers the API routes are served by.
+type Handlers struct {
+	Convert   *ConvertHandler
+	Quote     *QuoteHandler
+	Currency  *CurrencyHandler
+	Rates     *RatesHandler
+	WebSocket *WebSocketHandler
+	Alerts    *AlertHandler
+	Keys      *APIKeyHandler
+}
+
+// RegisterRoutes serves routes under APIVersion and, as deprecated aliases,
+// at their unversioned paths. Every request's parameters, and its JSON body
+// when the route has a Body schema, are validated against the route before
+// reaching the handler; bodies are read once, up to maxBodyBytes. Routes
+// with a Scope are first authenticated by auth, and every route is then
+// rate limited by limiter; nil leaves routes open or unlimited. Requests
+// count against their key's quota only once they pass rate limiting and
+// validation. The OpenAPI document
+// for routes is served at /v1/openapi.json, and the documentation of each
+// error code, the target of problem types, at /v1/errors/{code}.
+func RegisterRoutes(r *gin.Engine, routes []Route, auth *Authenticator, limiter *Limiter) {
+	v1 := r.Group(APIVersion)
+
+	for _, route := range routes {
+		handlers := []gin.HandlerFunc{validateParams(route.Params)}
+		if route.Body != nil {
+			handlers = append(handlers, validateBody(route.Body, route.itemErrors))
+		}
+		if auth != nil && route.Scope != "" {
+			handlers = append(handlers, auth.ChargeUsage())
+		}
+		handlers = append(handlers, route.Handle)
+		if limiter != nil {
+			handlers = append([]gin.HandlerFunc{limiter.Limit(route)}, handlers...)
+		}
+		if route.Body != nil {
+			handlers = append([]gin.HandlerFunc{readBody()}, handlers...)
+		}
+		if auth != nil && route.Scope != "" {
+			handlers = append([]gin.HandlerFunc{auth.Require(route.Scope)}, handlers...)
+		}
+
+		v1.Handle(route.Method, route.Path, handlers...)
+		r.Handle(route.Method, route.Path, append([]gin.HandlerFunc{deprecated(APIVersion + route.Path)}, handlers...)...)
+	}
+
+	document := NewOpenAPIDocument(routes)
+	v1.GET("/openapi.json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, document)
+	})
+	v1.GET("/errors/:code", describeErrorCode)
+}
+
+// Routes returns the API routes served by h.
+func Routes(h Handlers) []Route {
+	conversionParams := []Param{
+		currencyParam("from", "Source currency code", true),
+		currencyParam("to", "Target currency code", true),
+		amountParam("amount", "Amount of from to convert. Mutually exclusive with target_amount"),
+		amountParam("target_amount", "Amount of to to receive; the response gives the from amount required"),
+		dateParam("date", "Use historical rates for this date instead of the latest ones", false),
+	}
+
+	formattingParams := []Param{
+		{Name: "locale", Type: "string", Description: "BCP 47 locale for the formatted result; falls back to Accept-Language"},
+		{
+			Name:        "rounding",
+			Type:        "string",
+			Description: "Rounding mode",
+			Enum:        []string{string(money.RoundHalfUp), string(money.RoundHalfEven), string(money.RoundUp), string(money.RoundDown)},
+			Default:     string(money.RoundHalfUp),
+			invalid: func(value string) error {
+				return appErrors.InvalidRoundingError("unknown rounding mode " + value)
+			},
+		},
+		{
+			Name:        "precision",
+			Type:        "integer",
+			Description: "Decimal places to round to",
+			Minimum:     intPtr(0),
+			Maximum:     intPtr(18),
+			invalid: func(string) error {
+				return appErrors.InvalidRoundingError("precision must be between 0 and 18")
+			},
+		},
+	}
+
+	tableParams := []Param{
+		currencyParam("base", "Currency the rates are expressed against", false),
+		{Name: "symbols", Type: "string", Description: "Comma-separated currency codes to include; defaults to every supported currency"},
+	}
+	tableParams[0].Default = "USD"
+
+	tableFormats := formatParam(FormatJSON, FormatCSV, FormatXML)
+
+	conditionalParams := []Param{
+		{Name: "If-None-Match", In: "header", Type: "string", Description: "ETag of a cached response; 304 if unchanged"},
+		{Name: "If-Modified-Since", In: "header", Type: "string", Description: "Last-Modified of a cached response; 304 if unchanged"},
+	}
+
+	alertID := []Param{
+		{Name: "id", In: "path", Type: "string", Description: "Alert rule ID", Required: true},
+	}
+
+	keyID := []Param{
+		{Name: "id", In: "path", Type: "string", Description: "API key ID", Required: true},
+	}
+
+	rangeParams := []Param{
+		dateParam("start_date", "First day of the range", true),
+		dateParam("end_date", "Last day of the range, inclusive", true),
+	}
+
+	return []Route{
+		{
+			Method:      http.MethodGet,
+			Path:        "/convert",
+			OperationID: "convert",
+			Summary:     "Convert an amount between two currencies",
+			Params: concatParams(conversionParams, formattingParams, []Param{
+				{Name: "legacy", Type: "boolean", Description: "Return the original bare {\"amount\"} body"},
+				formatParam(AllFormats...),
+			}, conditionalParams),
+			Scope:  service.ScopeConvert,
+			Handle: h.Convert.HandleConvert,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/convert/batch",
+			OperationID: "convertBatch",
+			Summary:     "Convert many amounts at once",
+			Description: "Each item gets its own result or error; items on the same date share one rate lookup.",
+			Params:      concatParams(formattingParams, []Param{formatParam(AllFormats...)}),
+			Body:        batchSchema(),
+			Scope:       service.ScopeConvert,
+			Handle:      h.Convert.HandleBatch,
+			dates:       batchDates,
+			itemErrors:  true,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/quote",
+			OperationID: "quote",
+			Summary:     "Price a conversion with the client's spread and fee",
+			Params: concatParams(conversionParams, []Param{
+				{Name: "client", Type: "string", Description: "Client whose pricing rules apply"},
+				{Name: "X-Client-ID", In: "header", Type: "string", Description: "Client, when the client parameter is not given"},
+				tableFormats,
+			}),
+			Scope:  service.ScopeConvert,
+			Handle: h.Quote.HandleQuote,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/currencies",
+			OperationID: "listCurrencies",
+			Summary:     "List supported currencies with ISO 4217 metadata",
+			Scope:       service.ScopeRates,
+			Handle:      h.Currency.HandleCurrencies,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/rates",
+			OperationID: "getRates",
+			Summary:     "Get the rate table re-based to a currency",
+			Params: concatParams(tableParams, []Param{
+				dateParam("date", "Use historical rates for this date", false),
+				formatParam(AllFormats...),
+			}, formattingParams[1:], conditionalParams),
+			Scope:  service.ScopeRates,
+			Handle: h.Rates.HandleRates,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/rates/stream",
+			OperationID: "streamRates",
+			Summary:     "Stream the latest rate table as Server-Sent Events",
+			Description: "Sends a \"rates\" event now and after every refresh. Event IDs are snapshot versions; " +
+				"reconnect with Last-Event-ID to receive the refreshes missed in between.",
+			Params: concatParams(tableParams, formattingParams[1:], []Param{
+				{Name: "Last-Event-ID", In: "header", Type: "integer", Description: "ID of the last event received"},
+			}),
+			Produces: "text/event-stream",
+			Scope:    service.ScopeRates,
+			Handle:   h.Rates.HandleStream,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/rates/ws",
+			OperationID: "subscribePairs",
+			Summary:     "Subscribe to currency pairs over a WebSocket",
+			Description: "Send {\"action\": \"subscribe\", \"pair\": \"EUR/INR\", \"threshold\": \"0.5\"} to receive " +
+				"a \"rate\" message whenever a refresh moves the pair by at least threshold percent, and " +
+				"{\"action\": \"unsubscribe\", \"pair\": \"EUR/INR\"} to stop.",
+			Scope:  service.ScopeRates,
+			Handle: h.WebSocket.HandleWebSocket,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/rates/matrix",
+			OperationID: "getMatrix",
+			Summary:     "Get every supported currency against every other",
+			Params: concatParams([]Param{
+				dateParam("date", "Use historical rates for this date", false),
+				tableFormats,
+			}, formattingParams[1:], conditionalParams),
+			Scope:  service.ScopeRates,
+			Handle: h.Rates.HandleMatrix,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/timeseries",
+			OperationID: "getTimeseries",
+			Summary:     "Get daily rate tables for a date range",
+			Params:      concatParams(tableParams, rangeParams, []Param{tableFormats}, formattingParams[1:], conditionalParams),
+			Scope:       service.ScopeRates,
+			Handle:      h.Rates.HandleTimeseries,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/fluctuation",
+			OperationID: "getFluctuation",
+			Summary:     "Get how rates changed between two dates",
+			Params:      concatParams(tableParams, rangeParams, []Param{tableFormats}, formattingParams[1:], conditionalParams),
+			Scope:       service.ScopeRates,
+			Handle:      h.Rates.HandleFluctuation,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/alerts",
+			OperationID: "listAlerts",
+			Summary:     "List alert rules",
+			Scope:       service.ScopeAdmin,
+			Handle:      h.Alerts.HandleList,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/alerts",
+			OperationID: "createAlert",
+			Summary:     "Create an alert rule",
+			Description: "Rules are evaluated after every refresh of the latest rates; matches are POSTed to " +
+				"webhook_url, signed with the secret returned only in this response.",
+			Body:   alertSchema(),
+			Scope:  service.ScopeAdmin,
+			Handle: h.Alerts.HandleCreate,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/alerts/dead-letters",
+			OperationID: "listAlertDeadLetters",
+			Summary:     "List webhook deliveries that failed after every retry",
+			Scope:       service.ScopeAdmin,
+			Handle:      h.Alerts.HandleDeadLetters,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/alerts/:id",
+			OperationID: "getAlert",
+			Summary:     "Get an alert rule",
+			Params:      alertID,
+			Scope:       service.ScopeAdmin,
+			Handle:      h.Alerts.HandleGet,
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        "/alerts/:id",
+			OperationID: "updateAlert",
+			Summary:     "Replace an alert rule's settings",
+			Description: "The rule keeps its ID and secret and is evaluated afresh.",
+			Params:      alertID,
+			Body:        alertSchema(),
+			Scope:       service.ScopeAdmin,
+			Handle:      h.Alerts.HandleUpdate,
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        "/alerts/:id",
+			OperationID: "deleteAlert",
+			Summary:     "Delete an alert rule",
+			Params:      alertID,
+			Scope:       service.ScopeAdmin,
+			Handle:      h.Alerts.HandleDelete,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/keys",
+			OperationID: "listAPIKeys",
+			Summary:     "List API keys with their usage",
+			Scope:       service.ScopeAdmin,
+			Handle:      h.Keys.HandleList,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/keys",
+			OperationID: "issueAPIKey",
+			Summary:     "Issue an API key",
+			Description: "The key is returned only in this response; the service keeps its hash.",
+			Body:        apiKeySchema(),
+			Scope:       service.ScopeAdmin,
+			Handle:      h.Keys.HandleIssue,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/keys/:id",
+			OperationID: "getAPIKey",
+			Summary:     "Get an API key with its usage",
+			Params:      keyID,
+			Scope:       service.ScopeAdmin,
+			Handle:      h.Keys.HandleGet,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/keys/:id/rotate",
+			OperationID: "rotateAPIKey",
+			Summary:     "Replace an API key's secret",
+			Description: "The key keeps its ID, settings and usage. The previous secret stops working at once.",
+			Params:      keyID,
+			Scope:       service.ScopeAdmin,
+			Handle:      h.Keys.HandleRotate,
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        "/keys/:id",
+			OperationID: "revokeAPIKey",
+			Summary:     "Revoke an API key",
+			Description: "The key stops working and is kept, with its usage, for the record.",
+			Params:      keyID,
+			Scope:       service.ScopeAdmin,
+			Handle:      h.Keys.HandleRevoke,
+		},
+	}
+}
+
+// deprecated marks responses from an unversioned alias and points clients
+// at the versioned successor.
+func deprecated(successor string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Deprecation", "true")
+		c.Header("Link", "<"+successor+">; rel=\"successor-version\"")
+		c.Next()
+	}
+}
+
+// validateParams rejects requests whose parameters do not match params.
+// Empty values are treated as absent, as the handlers do.
+func validateParams(params []Param) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, param := range params {
+			var value string
+			switch param.In {
+			case "header":
+				value = c.GetHeader(param.Name)
+			case "path":
+				value = c.Param(param.Name)
+			default:
+				value = c.Query(param.Name)
+			}
+
+			if value == "" {
+				if param.Required {
+					respondWithError(c, appErrors.MissingParameterError(param.Name))
+					c.Abort()
+					return
+				}
+				continue
+			}
+
+			if reason := param.check(value); reason != "" {
+				var err error = appErrors.InvalidParameterError(param.Name, reason)
+				if param.invalid != nil {
+					err = param.invalid(value)
+				}
+
+				respondWithError(c, err)
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// check returns why value does not match the parameter schema, or "" if it
+// does.
+func (p Param) check(value string) string {
+	switch p.Type {
+	case "number":
+		if _, err := decimal.NewFromString(value); err != nil {
+			return "must be a number"
+		}
+	case "integer":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return "must be an integer"
+		}
+		if p.Minimum != nil && n < *p.Minimum {
+			return "must be at least " + strconv.Itoa(*p.Minimum)
+		}
+		if p.Maximum != nil && n > *p.Maximum {
+			return "must be at most " + strconv.Itoa(*p.Maximum)
+		}
+	case "boolean":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return "must be true or false"
+		}
+	}
+
+	if p.Format == "date" {
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return "must be a date in YYYY-MM-DD format"
+		}
+	}
+
+	if len(p.Enum) > 0 {
+		for _, allowed := range p.Enum {
+			if value == allowed {
+				return ""
+			}
+		}
+		return "must be one of the documented values"
+	}
+
+	return ""
+}
+
+func currencyParam(name, description string, required bool) Param {
+	return Param{Name: name, Type: "string", Description: description, Required: required}
+}
+
+func amountParam(name, description string) Param {
+	return Param{
+		Name:        name,
+		Type:        "number",
+		Description: description,
+		invalid: func(string) error {
+			return appErrors.InvalidAmountError()
+		},
+	}
+}
+
+func dateParam(name, description string, required bool) Param {
+	return Param{
+		Name:        name,
+		Type:        "string",
+		Format:      "date",
+		Description: description,
+		Required:    required,
+		invalid: func(string) error {
+			return appErrors.InvalidDateFormatError()
+		},
+	}
+}
+
+func batchSchema() *Schema {
+	return &Schema{
+		Type:     "array",
+		MaxItems: intPtr(service.MaxBatchSize),
+		Items: &Schema{
+			Type:     "object",
+			Required: []string{"from", "to", "amount"},
+			Properties: map[string]*Schema{
+				"from": {Type: "string", Description: "Source currency code"},
+				"to":   {Type: "string", Description: "Target currency code"},
+				"amount": {
+					Description: "Amount of from to convert",
+					OneOf:       []*Schema{{Type: "number"}, {Type: "string", Format: "decimal"}},
+				},
+				"date": {Type: "string", Format: "date", Description: "Use historical rates for this date"},
+			},
+		},
+	}
+}
+
+// formatParam documents the response formats a route can render, in
+// addition to negotiation through Accept.
+func formatParam(formats ...Format) Param {
+	names := make([]string, len(formats))
+	for i, format := range formats {
+		names[i] = string(format)
+	}
+
+	return Param{
+		Name:        "format",
+		Type:        "string",
+		Description: "Response format; takes precedence over the Accept header",
+		Enum:        names,
+		Default:     string(FormatJSON),
+	}
+}
+
+func alertSchema() *Schema {
+	return &Schema{
+		Type:     "object",
+		Required: []string{"pair", "condition", "threshold", "webhook_url"},
+		Properties: map[string]*Schema{
+			"pair": {Type: "string", Description: "Currency pair as FROM/TO, e.g. USD/INR"},
+			"condition": {
+				Type:        "string",
+				Enum:        []string{string(service.AlertAbove), string(service.AlertBelow), string(service.AlertChangePct)},
+				Description: "above or below: the rate crosses threshold; change_pct: the rate is threshold percent from the previous day's",
+			},
+			"threshold": {
+				Description: "Rate level, or percentage for change_pct",
+				OneOf:       []*Schema{{Type: "number"}, {Type: "string", Format: "decimal"}},
+			},
+			"cooldown":    {Type: "string", Default: "1h0m0s", Description: "Minimum time between notifications, as a Go duration"},
+			"webhook_url": {Type: "string", Format: "uri", Description: "http or https URL the alert is POSTed to"},
+		},
+	}
+}
+
+func apiKeySchema() *Schema {
+	scopes := make([]string, len(service.Scopes))
+	for i, scope := range service.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	return &Schema{
+		Type:     "object",
+		Required: []string{"name", "scopes"},
+		Properties: map[string]*Schema{
+			"name":        {Type: "string", Description: "Who or what the key is for"},
+			"scopes":      {Type: "array", Items: &Schema{Type: "string", Enum: scopes}, Description: "admin grants every scope"},
+			"daily_quota": {Type: "integer", Minimum: intPtr(0), Description: "Requests allowed per UTC day; 0 is unlimited"},
+			"expires_at":  {Type: "string", Format: "date-time", Description: "When the key stops working; never when omitted"},
+		},
+	}
+}
+
+func concatParams(groups ...[]Param) []Param {
+	var params []Param
+	for _, group := range groups {
+		params = append(params, group...)
+	}
+
+	return params
+}
+
+func intPtr(n int) *int {
+	return &n
+}
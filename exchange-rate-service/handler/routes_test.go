@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+	"github.com/yourusername/exchange-rate-service/service"
+)
+
+func TestOpenAPIDocumentCoversRoutes(t *testing.T) {
+	routes := Routes(Handlers{})
+	document := NewOpenAPIDocument(routes)
+
+	for _, route := range routes {
+		path := openAPIPath(route.Path)
+		op, ok := document.Paths[APIVersion+path][strings.ToLower(route.Method)]
+		if !ok {
+			t.Errorf("%s %s missing from document", route.Method, APIVersion+path)
+			continue
+		}
+		if len(op.Parameters) != len(route.Params) {
+			t.Errorf("%s has %d parameters, expected %d", route.Path, len(op.Parameters), len(route.Params))
+		}
+		if (route.Scope != "") != (len(op.Security) > 0) {
+			t.Errorf("%s %s: security %v does not match scope %q", route.Method, route.Path, op.Security, route.Scope)
+		}
+
+		for _, alias := range document.Paths[path] {
+			if !alias.Deprecated {
+				t.Errorf("Unversioned %s should be deprecated", route.Path)
+			}
+		}
+	}
+
+	for _, path := range []string{"/healthz", "/readyz", "/metrics", APIVersion + "/openapi.json", APIVersion + "/errors/{code}"} {
+		if _, ok := document.Paths[path]["get"]; !ok {
+			t.Errorf("GET %s missing from document", path)
+		}
+	}
+
+	codes := document.Components.Schemas["Error"].Properties["code"].Enum
+	if len(codes) != len(appErrors.Codes) {
+		t.Errorf("Error schema lists %d codes, expected %d", len(codes), len(appErrors.Codes))
+	}
+
+	if _, err := json.Marshal(document); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestRegisterRoutesValidatesParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	RegisterRoutes(r, []Route{{
+		Method: http.MethodGet,
+		Path:   "/echo",
+		Params: []Param{
+			dateParam("date", "", true),
+			amountParam("amount", ""),
+			{Name: "limit", Type: "integer", Minimum: intPtr(1)},
+		},
+		Handle: func(c *gin.Context) {
+			c.Status(http.StatusNoContent)
+		},
+	}}, nil, nil)
+
+	tests := []struct {
+		url    string
+		status int
+		code   appErrors.ErrorCode
+	}{
+		{"/v1/echo?date=2025-01-01&amount=10.5&limit=3", http.StatusNoContent, ""},
+		{"/v1/echo", http.StatusBadRequest, appErrors.ErrMissingParameter},
+		{"/v1/echo?date=01-01-2025", http.StatusBadRequest, appErrors.ErrInvalidDate},
+		{"/v1/echo?date=2025-01-01&amount=ten", http.StatusBadRequest, appErrors.ErrInvalidAmount},
+		{"/v1/echo?date=2025-01-01&limit=0", http.StatusBadRequest, appErrors.ErrInvalidParameter},
+		{"/echo?date=2025-01-01", http.StatusNoContent, ""},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+
+		if w.Code != tt.status {
+			t.Errorf("%s: status %d, expected %d", tt.url, w.Code, tt.status)
+			continue
+		}
+
+		if tt.code != "" {
+			var body map[string]any
+			json.Unmarshal(w.Body.Bytes(), &body)
+			if body["code"] != string(tt.code) {
+				t.Errorf("%s: code %v, expected %s", tt.url, body["code"], tt.code)
+			}
+		}
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/echo?date=2025-01-01", nil))
+	if w.Header().Get("Deprecation") != "true" {
+		t.Error("Expected Deprecation header on unversioned path")
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("openapi.json status %d", w.Code)
+	}
+}
+
+func TestRegisterRoutesChargesOnlyAcceptedRequests(t *testing.T) {
+	t.Setenv("API_KEYS_FILE", "")
+	t.Setenv("API_ADMIN_KEY", "")
+	t.Setenv("RATE_LIMIT_CACHED", "2/1m")
+	t.Setenv("RATE_LIMIT_REDIS_URL", "")
+	keys := service.NewAPIKeyService()
+
+	key, secret, err := keys.IssueKey(service.APIKeySpec{Name: "app", Scopes: []string{"rates"}, DailyQuota: 5})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	RegisterRoutes(r, []Route{{
+		Method: http.MethodGet,
+		Path:   "/echo",
+		Scope:  service.ScopeRates,
+		Params: []Param{{Name: "limit", Type: "integer", Minimum: intPtr(1)}},
+		Handle: func(c *gin.Context) {
+			c.Status(http.StatusNoContent)
+		},
+	}}, NewAuthenticator(keys, nil, nil), NewLimiter(service.NewRateLimiter(), nil))
+
+	tests := []struct {
+		url    string
+		status int
+	}{
+		{"/v1/echo?limit=0", http.StatusBadRequest},
+		{"/v1/echo?limit=1", http.StatusNoContent},
+		{"/v1/echo?limit=1", http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+		req.Header.Set("X-API-Key", secret)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s: status %d, expected %d", tt.url, w.Code, tt.status)
+		}
+	}
+
+	key, err = keys.Key(key.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if key.Usage.Total != 1 {
+		t.Errorf("Key charged for %d requests, expected only the accepted one", key.Usage.Total)
+	}
+}
+
+func TestRegisterRoutesValidatesBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	echo := func(c *gin.Context) {
+		body, _ := c.GetRawData()
+		c.String(http.StatusOK, "%s", body)
+	}
+	RegisterRoutes(r, []Route{
+		{Method: http.MethodPost, Path: "/keys", Body: apiKeySchema(), Handle: echo},
+		{Method: http.MethodPost, Path: "/alerts", Body: alertSchema(), Handle: echo},
+		{Method: http.MethodPost, Path: "/batch", Body: batchSchema(), Handle: echo, itemErrors: true},
+	}, nil, nil)
+
+	tests := []struct {
+		path   string
+		body   string
+		status int
+		code   appErrors.ErrorCode
+	}{
+		{"/v1/keys", `{"name":"app","scopes":["rates"],"daily_quota":10}`, http.StatusOK, ""},
+		{"/v1/keys", `{"name":"app"}`, http.StatusBadRequest, appErrors.ErrMissingParameter},
+		{"/v1/keys", `{"name":"app","scopes":["write"]}`, http.StatusBadRequest, appErrors.ErrInvalidParameter},
+		{"/v1/keys", `{"name":"app","scopes":["rates"],"daily_quota":-1}`, http.StatusBadRequest, appErrors.ErrInvalidParameter},
+		{"/v1/keys", `{"name":"app","scopes":["rates"],"expires_at":"tomorrow"}`, http.StatusBadRequest, appErrors.ErrInvalidParameter},
+		{"/v1/keys", `{"name":`, http.StatusBadRequest, appErrors.ErrInvalidRequestBody},
+		{"/v1/keys", `{"name":"` + strings.Repeat("a", maxBodyBytes) + `"}`, http.StatusRequestEntityTooLarge, appErrors.ErrRequestTooLarge},
+		{"/v1/alerts", `{"pair":"USD/INR","condition":"above","threshold":"83.5","webhook_url":"https://example.com/hook"}`, http.StatusOK, ""},
+		{"/v1/alerts", `{"pair":"USD/INR","condition":"above","threshold":83.5,"webhook_url":"https://example.com/hook"}`, http.StatusOK, ""},
+		{"/v1/alerts", `{"pair":"USD/INR","condition":"above","threshold":"high","webhook_url":"https://example.com/hook"}`, http.StatusBadRequest, appErrors.ErrInvalidParameter},
+		{"/v1/alerts", `{"pair":"USD/INR","condition":"sideways","threshold":1,"webhook_url":"https://example.com/hook"}`, http.StatusBadRequest, appErrors.ErrInvalidParameter},
+		{"/v1/batch", `[{"from":"USD"},{"amount":true}]`, http.StatusOK, ""},
+		{"/v1/batch", `{"from":"USD"}`, http.StatusBadRequest, appErrors.ErrInvalidParameter},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body)))
+
+		if w.Code != tt.status {
+			t.Errorf("%s %.100s: status %d, expected %d", tt.path, tt.body, w.Code, tt.status)
+			continue
+		}
+
+		if tt.code != "" {
+			var body map[string]any
+			json.Unmarshal(w.Body.Bytes(), &body)
+			if body["code"] != string(tt.code) {
+				t.Errorf("%s %.100s: code %v, expected %s", tt.path, tt.body, body["code"], tt.code)
+			}
+		} else if w.Body.String() != tt.body {
+			t.Errorf("%s: handler got body %q, expected it unchanged", tt.path, w.Body.String())
+		}
+	}
+}
+
+func TestDescribeErrorCode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	RegisterRoutes(r, nil, nil, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, problemType(appErrors.ErrRatesUnavailable), nil))
+
+	var body map[string]any
+	json.Unmarshal(w.Body.Bytes(), &body)
+	if w.Code != http.StatusOK || body["status"] != float64(http.StatusServiceUnavailable) {
+		t.Errorf("Status %d, body %v", w.Code, body)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/errors/NOPE", nil))
+	if w.Code != http.StatusNotFound || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/problem+json") {
+		t.Errorf("Unknown code: status %d, Content-Type %s", w.Code, w.Header().Get("Content-Type"))
+	}
+}
@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+)
+
+// validateBody checks the JSON request body read by readBody against
+// schema. Missing required fields return MISSING_PARAMETER and mismatched
+// values INVALID_PARAMETER, named by their path in the body. With
+// skipItems, only the array itself is checked: the handler reports errors
+// for each item.
+func validateBody(schema *Schema, skipItems bool) gin.HandlerFunc {
+	if skipItems {
+		shallow := *schema
+		shallow.Items = nil
+		shallow.MaxItems = nil
+		schema = &shallow
+	}
+
+	return func(c *gin.Context) {
+		value, err := bodyValue(c)
+		if err != nil {
+			respondWithError(c, err)
+			c.Abort()
+			return
+		}
+
+		if err := schema.validate(value, "body"); err != nil {
+			respondWithError(c, err)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// validate returns why value, found at path, does not match s, or nil if
+// it does.
+func (s *Schema) validate(value any, path string) error {
+	if len(s.OneOf) > 0 {
+		matches := 0
+		for _, option := range s.OneOf {
+			if option.validate(value, path) == nil {
+				matches++
+			}
+		}
+		if matches != 1 {
+			return appErrors.InvalidParameterError(path, "does not match any of the allowed types")
+		}
+		return nil
+	}
+
+	switch s.Type {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return appErrors.InvalidParameterError(path, "must be an object")
+		}
+		for _, name := range s.Required {
+			if field, ok := object[name]; !ok || field == nil || field == "" {
+				return appErrors.MissingParameterError(fieldPath(path, name))
+			}
+		}
+		for name, property := range s.Properties {
+			if field, ok := object[name]; ok && field != nil {
+				if err := property.validate(field, fieldPath(path, name)); err != nil {
+					return err
+				}
+			}
+		}
+
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return appErrors.InvalidParameterError(path, "must be an array")
+		}
+		if s.MaxItems != nil && len(items) > *s.MaxItems {
+			return appErrors.InvalidParameterError(path, "must have at most "+strconv.Itoa(*s.MaxItems)+" items")
+		}
+		if s.Items != nil {
+			for i, item := range items {
+				if err := s.Items.validate(item, path+"["+strconv.Itoa(i)+"]"); err != nil {
+					return err
+				}
+			}
+		}
+
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return appErrors.InvalidParameterError(path, "must be a string")
+		}
+		if reason := checkStringFormat(s.Format, text); reason != "" {
+			return appErrors.InvalidParameterError(path, reason)
+		}
+		if len(s.Enum) > 0 && !contains(s.Enum, text) {
+			return appErrors.InvalidParameterError(path, "must be one of the documented values")
+		}
+
+	case "number", "integer":
+		number, ok := value.(json.Number)
+		if !ok {
+			return appErrors.InvalidParameterError(path, "must be a "+s.Type)
+		}
+		if s.Type == "integer" {
+			n, err := number.Int64()
+			if err != nil {
+				return appErrors.InvalidParameterError(path, "must be an integer")
+			}
+			if s.Minimum != nil && n < int64(*s.Minimum) {
+				return appErrors.InvalidParameterError(path, "must be at least "+strconv.Itoa(*s.Minimum))
+			}
+			if s.Maximum != nil && n > int64(*s.Maximum) {
+				return appErrors.InvalidParameterError(path, "must be at most "+strconv.Itoa(*s.Maximum))
+			}
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return appErrors.InvalidParameterError(path, "must be true or false")
+		}
+	}
+
+	return nil
+}
+
+func checkStringFormat(format, value string) string {
+	switch format {
+	case "date":
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return "must be a date in YYYY-MM-DD format"
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return "must be an RFC 3339 time"
+		}
+	case "decimal":
+		if _, err := decimal.NewFromString(value); err != nil {
+			return "must be a decimal number"
+		}
+	case "uri":
+		if u, err := url.Parse(value); err != nil || u.Scheme == "" || u.Host == "" {
+			return "must be an absolute URL"
+		}
+	}
+
+	return ""
+}
+
+func fieldPath(path, name string) string {
+	if path == "body" {
+		return name
+	}
+
+	return path + "." + name
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+
+	return false
+}
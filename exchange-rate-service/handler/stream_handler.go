@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/exchange-rate-service/money"
+	"github.com/yourusername/exchange-rate-service/service"
+)
+
+// streamHeartbeat is how often an idle stream sends a comment line, so
+// proxies do not close it between hourly refreshes.
+const streamHeartbeat = 30 * time.Second
+
+// HandleStream sends the latest rate table as a Server-Sent Event, then a
+// new event after every refresh. Event IDs are the snapshot versions, so a
+// client reconnecting with Last-Event-ID first receives the refreshes it
+// missed.
+func (h *RatesHandler) HandleStream(c *gin.Context) {
+	base := c.DefaultQuery("base", "USD")
+	symbols := parseSymbols(c.Query("symbols"))
+
+	rounding, err := parseRounding(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	updates, cancel := h.rateFetcher.WatchRates()
+	defer cancel()
+
+	table, err := h.rateFetcher.GetRates(base, symbols, nil)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	stream := &rateStream{c: c, rateFetcher: h.rateFetcher, base: base, symbols: symbols, rounding: rounding}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	resumed := false
+	if lastID, err := strconv.ParseUint(c.GetHeader("Last-Event-ID"), 10, 64); err == nil {
+		stream.version = lastID
+		resumed = stream.catchUp()
+	}
+	if !resumed {
+		stream.send(table)
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for !stream.done {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-heartbeat.C:
+			c.Writer.WriteString(": heartbeat\n\n")
+			c.Writer.Flush()
+		case snapshot := <-updates:
+			if stream.catchUp() {
+				continue
+			}
+
+			table, err := h.rateFetcher.RebaseSnapshot(base, symbols, snapshot)
+			if err != nil {
+				stream.sendError(err)
+				continue
+			}
+			stream.send(table)
+		}
+	}
+}
+
+// rateStream tracks the last snapshot version sent to one SSE client.
+type rateStream struct {
+	c           *gin.Context
+	rateFetcher *service.RateFetcherService
+	base        string
+	symbols     []string
+	rounding    money.Rounding
+	version     uint64
+
+	// done is set once the client has gone or an error event ended the
+	// stream.
+	done bool
+}
+
+// catchUp sends every retained refresh newer than the last one sent. It
+// returns false when the history no longer reaches back that far.
+func (s *rateStream) catchUp() bool {
+	snapshots, ok := s.rateFetcher.SnapshotsSince(s.version)
+	if !ok {
+		return false
+	}
+
+	for _, snapshot := range snapshots {
+		if s.done {
+			break
+		}
+
+		table, err := s.rateFetcher.RebaseSnapshot(s.base, s.symbols, snapshot)
+		if err != nil {
+			s.sendError(err)
+			break
+		}
+		s.send(table)
+	}
+
+	return true
+}
+
+// send writes table as a "rates" event.
+func (s *rateStream) send(table *service.RateTable) {
+	s.c.Render(-1, sse.Event{
+		Id:    strconv.FormatUint(table.Snapshot.Version, 10),
+		Event: "rates",
+		Data:  rateTableResponse(table, s.rounding),
+	})
+	s.c.Writer.Flush()
+	s.version = table.Snapshot.Version
+	s.done = s.c.Request.Context().Err() != nil
+}
+
+func (s *rateStream) sendError(err error) {
+	_, body := errorResponse(err)
+	s.c.Render(-1, sse.Event{
+		Event: "error",
+		Data:  body,
+	})
+	s.c.Writer.Flush()
+	s.done = true
+}
@@ -0,0 +1,352 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+	"github.com/yourusername/exchange-rate-service/service"
+)
+
+const (
+	wsWriteWait        = 10 * time.Second
+	wsPongWait         = 60 * time.Second
+	wsPingInterval     = 30 * time.Second
+	wsReadLimit        = 4096
+	wsSendBuffer       = 32
+	wsMaxSubscriptions = 50
+	// wsRecheckInterval is how often a connection's credentials are
+	// checked again, so revoked keys and expired tokens are disconnected.
+	wsRecheckInterval = time.Minute
+)
+
+// WebSocketHandler pushes rate changes for subscribed currency pairs.
+type WebSocketHandler struct {
+	rateFetcher     *service.RateFetcherService
+	upgrader        websocket.Upgrader
+	recheckInterval time.Duration
+}
+
+// NewWebSocketHandler accepts browser connections from the origins listed
+// in WS_ALLOWED_ORIGINS, separated by commas, or from any origin when it
+// is "*". When it is not set only same-origin pages may connect. Clients
+// that send no Origin header, which browsers always do, are accepted.
+func NewWebSocketHandler(rateFetcher *service.RateFetcherService) *WebSocketHandler {
+	h := &WebSocketHandler{
+		rateFetcher:     rateFetcher,
+		recheckInterval: wsRecheckInterval,
+	}
+
+	if value := os.Getenv("WS_ALLOWED_ORIGINS"); value != "" {
+		origins, err := parseOrigins(value)
+		if err != nil {
+			panic(fmt.Sprintf("invalid WS_ALLOWED_ORIGINS: %v", err))
+		}
+		h.upgrader.CheckOrigin = func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			return origin == "" || origins["*"] || origins[strings.ToLower(origin)]
+		}
+	}
+
+	return h
+}
+
+// parseOrigins reads a comma-separated list of origins such as
+// https://dashboard.example.com, or "*".
+func parseOrigins(value string) (map[string]bool, error) {
+	origins := make(map[string]bool)
+	for _, origin := range strings.Split(value, ",") {
+		origin = strings.ToLower(strings.TrimSpace(origin))
+		if origin == "*" {
+			origins[origin] = true
+			continue
+		}
+
+		parsed, err := url.Parse(origin)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" || parsed.Path != "" {
+			return nil, fmt.Errorf("%q is not an origin such as https://example.com", origin)
+		}
+		origins[origin] = true
+	}
+
+	return origins, nil
+}
+
+// wsRequest is a message from the client, for example
+// {"action": "subscribe", "pair": "EUR/INR", "threshold": "0.5"}.
+type wsRequest struct {
+	Action    string `json:"action"`
+	Pair      string `json:"pair"`
+	Threshold string `json:"threshold"`
+}
+
+// HandleWebSocket upgrades the request and serves pair subscriptions until
+// the client disconnects. The route requires the rates scope; the
+// connection is closed once its credentials no longer grant it.
+func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+
+	client := &wsClient{
+		conn:          conn,
+		rateFetcher:   h.rateFetcher,
+		send:          make(chan gin.H, wsSendBuffer),
+		done:          make(chan struct{}),
+		subscriptions: make(map[service.Pair]*service.PairSubscription),
+	}
+
+	updates, cancel := h.rateFetcher.WatchRates()
+	defer cancel()
+
+	if check, ok := requestCredentialCheck(c); ok {
+		go client.recheck(check, h.recheckInterval)
+	}
+
+	go client.writePump()
+	go client.watch(updates)
+	client.readPump()
+}
+
+// wsClient is one WebSocket connection. Only writePump writes to conn.
+type wsClient struct {
+	conn        *websocket.Conn
+	rateFetcher *service.RateFetcherService
+
+	// send buffers outgoing messages. A client that lets it fill up is
+	// disconnected rather than slowing down the others.
+	send chan gin.H
+
+	done      chan struct{}
+	closeOnce sync.Once
+	closeCode int
+	closeText string
+
+	subscriptions map[service.Pair]*service.PairSubscription
+	mu            sync.Mutex
+}
+
+func (c *wsClient) close(code int, text string) {
+	c.closeOnce.Do(func() {
+		c.closeCode, c.closeText = code, text
+		close(c.done)
+	})
+}
+
+// enqueue queues message for writePump, closing the connection if the
+// client is too far behind.
+func (c *wsClient) enqueue(message gin.H) {
+	select {
+	case c.send <- message:
+	case <-c.done:
+	default:
+		c.close(websocket.ClosePolicyViolation, "too many pending messages")
+	}
+}
+
+func (c *wsClient) readPump() {
+	defer c.close(websocket.CloseNormalClosure, "")
+
+	c.conn.SetReadLimit(wsReadLimit)
+	c.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var request wsRequest
+		if err := json.Unmarshal(data, &request); err != nil {
+			c.enqueueError(appErrors.InvalidRequestBodyError(err))
+			continue
+		}
+
+		c.handle(request)
+	}
+}
+
+func (c *wsClient) writePump() {
+	ticker := time.NewTicker(wsPingInterval)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case message := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := c.conn.WriteJSON(message); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-c.done:
+			message := websocket.FormatCloseMessage(c.closeCode, c.closeText)
+			c.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(wsWriteWait))
+			return
+		}
+	}
+}
+
+// watch reports subscribed pairs that moved beyond their threshold after
+// each refresh.
+func (c *wsClient) watch(updates <-chan *service.RateSnapshot) {
+	for {
+		select {
+		case <-c.done:
+			return
+		case snapshot, ok := <-updates:
+			if !ok {
+				return
+			}
+			c.notify(snapshot)
+		}
+	}
+}
+
+// recheck checks the connection's credentials every interval, closing it
+// with a policy violation once they are missing, revoked or expired, or no
+// longer grant the rates scope. Other failures, such as a used up quota or
+// unavailable signing keys, leave it open.
+func (c *wsClient) recheck(check credentialCheck, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			var customErr *appErrors.CustomError
+			if err := check(context.Background()); errors.As(err, &customErr) &&
+				(customErr.Category == appErrors.CategoryAuth || customErr.Category == appErrors.CategoryForbidden) {
+				c.close(websocket.ClosePolicyViolation, "credentials are no longer valid")
+				return
+			}
+		}
+	}
+}
+
+func (c *wsClient) notify(snapshot *service.RateSnapshot) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for pair, subscription := range c.subscriptions {
+		table, err := c.rateFetcher.RebaseSnapshot(pair.From, []string{pair.To}, snapshot)
+		if err != nil {
+			c.enqueueError(err)
+			continue
+		}
+
+		previous := subscription.Last()
+		rate := table.Rates[pair.To]
+		if changed, change := subscription.Update(rate); changed {
+			c.enqueue(gin.H{
+				"type":          "rate",
+				"pair":          pair.String(),
+				"rate":          rate.String(),
+				"previous_rate": previous.String(),
+				"change_pct":    change.Round(4).String(),
+				"timestamp":     snapshot.UpdatedAt.UTC().Format(time.RFC3339),
+				"version":       snapshot.Version,
+			})
+		}
+	}
+}
+
+func (c *wsClient) handle(request wsRequest) {
+	switch request.Action {
+	case "subscribe":
+		c.subscribe(request)
+	case "unsubscribe":
+		c.unsubscribe(request)
+	default:
+		c.enqueueError(appErrors.UnknownActionError(request.Action))
+	}
+}
+
+func (c *wsClient) subscribe(request wsRequest) {
+	pair, err := c.rateFetcher.ParsePair(request.Pair)
+	if err != nil {
+		c.enqueueError(err)
+		return
+	}
+
+	table, err := c.rateFetcher.GetRates(pair.From, []string{pair.To}, nil)
+	if err != nil {
+		c.enqueueError(err)
+		return
+	}
+
+	rate := table.Rates[pair.To]
+	subscription, err := service.NewPairSubscription(pair, request.Threshold, rate)
+	if err != nil {
+		c.enqueueError(err)
+		return
+	}
+
+	c.mu.Lock()
+	_, exists := c.subscriptions[pair]
+	if !exists && len(c.subscriptions) >= wsMaxSubscriptions {
+		c.mu.Unlock()
+		c.enqueueError(appErrors.TooManySubscriptionsError(wsMaxSubscriptions))
+		return
+	}
+	c.subscriptions[pair] = subscription
+	c.mu.Unlock()
+
+	c.enqueue(gin.H{
+		"type":      "subscribed",
+		"pair":      pair.String(),
+		"rate":      rate.String(),
+		"threshold": subscription.Threshold.String(),
+		"timestamp": table.Snapshot.UpdatedAt.UTC().Format(time.RFC3339),
+		"version":   table.Snapshot.Version,
+	})
+}
+
+func (c *wsClient) unsubscribe(request wsRequest) {
+	pair, err := c.rateFetcher.ParsePair(request.Pair)
+	if err != nil {
+		c.enqueueError(err)
+		return
+	}
+
+	c.mu.Lock()
+	delete(c.subscriptions, pair)
+	c.mu.Unlock()
+
+	c.enqueue(gin.H{
+		"type": "unsubscribed",
+		"pair": pair.String(),
+	})
+}
+
+func (c *wsClient) enqueueError(err error) {
+	_, problem := errorResponse(err)
+	c.enqueue(gin.H{
+		"type":  "error",
+		"error": problem,
+	})
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+	"github.com/yourusername/exchange-rate-service/service"
+)
+
+// wsTestServer serves the /rates/ws route behind an authenticator.
+type wsTestServer struct {
+	url     string
+	secret  string // an API key with the rates scope
+	keyID   string
+	keys    *service.APIKeyService
+	handler *WebSocketHandler
+}
+
+func newWebSocketServer(t *testing.T) *wsTestServer {
+	t.Helper()
+	t.Setenv("API_KEYS_FILE", "")
+	t.Setenv("API_ADMIN_KEY", "")
+	keys := service.NewAPIKeyService()
+
+	key, secret, err := keys.IssueKey(service.APIKeySpec{Name: "dashboard", Scopes: []string{"rates"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	handler := NewWebSocketHandler(&service.RateFetcherService{})
+	routes := Routes(Handlers{WebSocket: handler})
+	for _, route := range routes {
+		if route.Path == "/rates/ws" {
+			RegisterRoutes(r, []Route{route}, NewAuthenticator(keys, nil, nil), nil)
+		}
+	}
+
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	return &wsTestServer{
+		url:     "ws" + strings.TrimPrefix(server.URL, "http") + "/v1/rates/ws",
+		secret:  secret,
+		keyID:   key.ID,
+		keys:    keys,
+		handler: handler,
+	}
+}
+
+func TestWebSocketRequiresAPIKey(t *testing.T) {
+	server := newWebSocketServer(t)
+	url, secret := server.url, server.secret
+
+	for _, query := range []string{"", "?api_key=erk_wrong"} {
+		_, resp, err := websocket.DefaultDialer.Dial(url+query, nil)
+		if err == nil {
+			t.Fatalf("Expected %s to be rejected", query)
+		}
+		if resp == nil || resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Dial(%q) response %v, expected 401", query, resp)
+		}
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, http.Header{"X-API-Key": {secret}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	conn.Close()
+}
+
+func TestWebSocketRejectsBadMessages(t *testing.T) {
+	server := newWebSocketServer(t)
+	url := server.url + "?api_key=" + server.secret
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer conn.Close()
+
+	tests := []struct {
+		message string
+		code    appErrors.ErrorCode
+	}{
+		{`{"action": "buy", "pair": "EUR/INR"}`, appErrors.ErrUnknownAction},
+		{`not json`, appErrors.ErrInvalidRequestBody},
+	}
+
+	for _, tt := range tests {
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(tt.message)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var reply struct {
+			Type  string         `json:"type"`
+			Error map[string]any `json:"error"`
+		}
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		if err := conn.ReadJSON(&reply); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if reply.Type != "error" || reply.Error["code"] != string(tt.code) {
+			t.Errorf("Reply to %s = %v, expected %s error", tt.message, reply, tt.code)
+		}
+	}
+}
+
+func TestWebSocketChecksOrigin(t *testing.T) {
+	tests := []struct {
+		allowed string
+		origin  string
+		status  int
+	}{
+		{"", "", http.StatusSwitchingProtocols},
+		{"", "https://evil.example.com", http.StatusForbidden},
+		{"https://dash.example.com", "https://dash.example.com", http.StatusSwitchingProtocols},
+		{"https://dash.example.com, https://ops.example.com", "https://OPS.example.com", http.StatusSwitchingProtocols},
+		{"https://dash.example.com", "https://evil.example.com", http.StatusForbidden},
+		{"https://dash.example.com", "", http.StatusSwitchingProtocols},
+		{"*", "https://evil.example.com", http.StatusSwitchingProtocols},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("WS_ALLOWED_ORIGINS", tt.allowed)
+		server := newWebSocketServer(t)
+
+		header := http.Header{"X-API-Key": {server.secret}}
+		if tt.origin != "" {
+			header.Set("Origin", tt.origin)
+		}
+		conn, resp, _ := websocket.DefaultDialer.Dial(server.url, header)
+		if conn != nil {
+			conn.Close()
+		}
+		if resp == nil || resp.StatusCode != tt.status {
+			t.Errorf("Origin %q with allowlist %q: response %v, expected %d", tt.origin, tt.allowed, resp, tt.status)
+		}
+	}
+}
+
+func TestParseOriginsRejectsInvalidOrigins(t *testing.T) {
+	for _, value := range []string{"dash.example.com", "ftp://dash.example.com", "https://dash.example.com/app"} {
+		if _, err := parseOrigins(value); err == nil {
+			t.Errorf("parseOrigins(%q) expected an error", value)
+		}
+	}
+}
+
+func TestWebSocketClosesWhenKeyIsRevoked(t *testing.T) {
+	server := newWebSocketServer(t)
+	server.handler.recheckInterval = 10 * time.Millisecond
+
+	conn, _, err := websocket.DefaultDialer.Dial(server.url, http.Header{"X-API-Key": {server.secret}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer conn.Close()
+
+	if err := server.keys.RevokeKey(server.keyID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, _, err = conn.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.ClosePolicyViolation) {
+		t.Errorf("Read after revoking the key: %v, expected a policy violation close", err)
+	}
+}
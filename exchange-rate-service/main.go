@@ -1,12 +1,16 @@
 package main
 
 import (
+	"context"
 	"log"
+	"net"
 	"os"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/yourusername/exchange-rate-service/grpcserver"
 	"github.com/yourusername/exchange-rate-service/handler"
+	"github.com/yourusername/exchange-rate-service/metrics"
 	"github.com/yourusername/exchange-rate-service/service"
 )
 
@@ -21,15 +25,63 @@ func main() {
 		port = "8080"
 	}
 
+	grpcPort := os.Getenv("GRPC_PORT")
+	if grpcPort == "" {
+		grpcPort = "9090"
+	}
+
 	rateFetcher := service.NewRateFetcherService()
 
 	rateFetcher.StartHourlyRefresh()
 
+	alertService := service.NewAlertService(rateFetcher)
+	alertService.Start()
+
+	apiKeyService := service.NewAPIKeyService()
+	apiKeyService.Start()
+
+	tokenVerifier := service.NewTokenVerifier()
+	rateLimiter := service.NewRateLimiter()
+
+	listener, err := net.Listen("tcp", ":"+grpcPort)
+	if err != nil {
+		log.Fatalf("Failed to listen on gRPC port: %v", err)
+	}
+
+	grpcServer := grpcserver.NewGRPCServer(context.Background(), rateFetcher, apiKeyService, tokenVerifier, rateLimiter)
+	go func() {
+		log.Printf("gRPC server running on port: %s\n", grpcPort)
+		if err := grpcServer.Serve(listener); err != nil {
+			log.Fatalf("Failed to start gRPC server: %v", err)
+		}
+	}()
+
 	convertHandler := handler.NewConvertHandler(rateFetcher)
+	quoteHandler := handler.NewQuoteHandler(rateFetcher)
+	currencyHandler := handler.NewCurrencyHandler(rateFetcher)
+	ratesHandler := handler.NewRatesHandler(rateFetcher)
+	webSocketHandler := handler.NewWebSocketHandler(rateFetcher)
+	healthHandler := handler.NewHealthHandler(rateFetcher)
+	alertHandler := handler.NewAlertHandler(alertService)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
 	gin.SetMode(gin.DebugMode)
-	r := gin.Default()
+	r := gin.New()
+	r.Use(handler.RequestLogger(), gin.Recovery(), metrics.Middleware())
+	metrics.SetRateSource(rateFetcher)
+
+	handler.RegisterRoutes(r, handler.Routes(handler.Handlers{
+		Convert:   convertHandler,
+		Quote:     quoteHandler,
+		Currency:  currencyHandler,
+		Rates:     ratesHandler,
+		WebSocket: webSocketHandler,
+		Alerts:    alertHandler,
+		Keys:      apiKeyHandler,
+	}), handler.NewAuthenticator(apiKeyService, tokenVerifier, rateLimiter), handler.NewLimiter(rateLimiter, rateFetcher))
 
-	r.GET("/convert", convertHandler.HandleConvert)
+	r.GET("/healthz", healthHandler.HandleLiveness)
+	r.GET("/readyz", healthHandler.HandleReadiness)
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
 
 	log.Println("Exchange Rate Service Started")
 	log.Printf("Server running on port: %s\n", port)
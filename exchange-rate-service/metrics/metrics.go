@@ -0,0 +1,166 @@
+// Package metrics exposes the service's Prometheus metrics.
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+)
+
+const namespace = "exchange_rate"
+
+// ErrorCodeKey is the gin context key handlers store the ErrorCode of a
+// failed request under, so it can be used as a label.
+const ErrorCodeKey = "error_code"
+
+// RateSource reports the cache state exported as gauges.
+type RateSource interface {
+	LatestRatesAge() time.Duration
+	HistoricalDates() int
+}
+
+var (
+	registry = prometheus.NewRegistry()
+
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by route, method, status and error code.",
+	}, []string{"route", "method", "status", "error_code"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route, method and error code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "error_code"})
+
+	upstreamRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upstream_requests_total",
+		Help:      "Calls to rate providers by provider and endpoint.",
+	}, []string{"provider", "endpoint"})
+
+	upstreamFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upstream_failures_total",
+		Help:      "Failed calls to rate providers by provider, endpoint and error code.",
+	}, []string{"provider", "endpoint", "error_code"})
+
+	upstreamDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "upstream_request_duration_seconds",
+		Help:      "Latency of calls to rate providers by provider and endpoint.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"provider", "endpoint"})
+
+	cacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_lookups_total",
+		Help:      "Rate cache lookups by cache (latest or historical) and result (hit or miss).",
+	}, []string{"cache", "result"})
+
+	rateSource atomic.Pointer[RateSource]
+)
+
+func init() {
+	registry.MustRegister(
+		httpRequests,
+		httpDuration,
+		upstreamRequests,
+		upstreamFailures,
+		upstreamDuration,
+		cacheLookups,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "latest_rates_age_seconds",
+			Help:      "Seconds since the latest rates were fetched.",
+		}, func() float64 {
+			if source := rateSource.Load(); source != nil {
+				return (*source).LatestRatesAge().Seconds()
+			}
+			return 0
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "historical_dates_cached",
+			Help:      "Number of dates with cached historical rates.",
+		}, func() float64 {
+			if source := rateSource.Load(); source != nil {
+				return float64((*source).HistoricalDates())
+			}
+			return 0
+		}),
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// SetRateSource makes source back the cache gauges.
+func SetRateSource(source RateSource) {
+	rateSource.Store(&source)
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// Middleware records the count and latency of every request, labelled with
+// the matched route and the ErrorCode the handler stored under
+// ErrorCodeKey.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		code := c.GetString(ErrorCodeKey)
+
+		httpRequests.WithLabelValues(route, c.Request.Method, strconv.Itoa(c.Writer.Status()), code).Inc()
+		httpDuration.WithLabelValues(route, c.Request.Method, code).Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObserveUpstream records a call to endpoint of provider that started at
+// start and failed with err, if not nil.
+func ObserveUpstream(provider, endpoint string, start time.Time, err error) {
+	upstreamRequests.WithLabelValues(provider, endpoint).Inc()
+	upstreamDuration.WithLabelValues(provider, endpoint).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		upstreamFailures.WithLabelValues(provider, endpoint, ErrorCode(err)).Inc()
+	}
+}
+
+// ObserveCacheLookup records a hit or miss in the named cache.
+func ObserveCacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+
+	cacheLookups.WithLabelValues(cache, result).Inc()
+}
+
+// ErrorCode returns the ErrorCode of err, or "UNKNOWN" for errors that are
+// not a CustomError.
+func ErrorCode(err error) string {
+	var customErr *appErrors.CustomError
+	if errors.As(err, &customErr) {
+		return string(customErr.Code)
+	}
+
+	return "UNKNOWN"
+}
@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+)
+
+type testSource struct{}
+
+func (testSource) LatestRatesAge() time.Duration { return 90 * time.Second }
+func (testSource) HistoricalDates() int          { return 3 }
+
+func TestMetricsExposition(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware())
+	r.GET("/v1/convert", func(c *gin.Context) {
+		c.Set(ErrorCodeKey, ErrorCode(appErrors.InvalidAmountError()))
+		c.Status(http.StatusBadRequest)
+	})
+	r.GET("/metrics", gin.WrapH(Handler()))
+
+	SetRateSource(testSource{})
+	ObserveUpstream("exchangerate.host", "live", time.Now(), appErrors.APIBadStatusError(500))
+	ObserveCacheLookup("historical", false)
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/convert", nil))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(w.Body)
+
+	expected := []string{
+		`exchange_rate_http_requests_total{error_code="INVALID_AMOUNT",method="GET",route="/v1/convert",status="400"} 1`,
+		`exchange_rate_http_request_duration_seconds_count{error_code="INVALID_AMOUNT",method="GET",route="/v1/convert"} 1`,
+		`exchange_rate_upstream_failures_total{endpoint="live",error_code="API_BAD_STATUS",provider="exchangerate.host"} 1`,
+		`exchange_rate_cache_lookups_total{cache="historical",result="miss"} 1`,
+		`exchange_rate_latest_rates_age_seconds 90`,
+		`exchange_rate_historical_dates_cached 3`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(string(body), line) {
+			t.Errorf("Missing %s", line)
+		}
+	}
+}
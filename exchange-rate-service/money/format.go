@@ -0,0 +1,138 @@
+package money
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+type symbolPosition int
+
+const (
+	symbolBefore symbolPosition = iota
+	symbolBeforeSpaced
+	symbolAfter
+)
+
+// symbolPositions holds the CLDR currency pattern placement for languages
+// that deviate from the "¤#,##0.00" default. golang.org/x/text ships the
+// symbols and number formats but not the currency patterns themselves.
+var symbolPositions = map[string]symbolPosition{
+	"bg": symbolAfter,
+	"cs": symbolAfter,
+	"da": symbolAfter,
+	"de": symbolAfter,
+	"el": symbolAfter,
+	"es": symbolAfter,
+	"et": symbolAfter,
+	"fi": symbolAfter,
+	"fr": symbolAfter,
+	"hr": symbolAfter,
+	"hu": symbolAfter,
+	"it": symbolAfter,
+	"lt": symbolAfter,
+	"lv": symbolAfter,
+	"nb": symbolAfter,
+	"nl": symbolBeforeSpaced,
+	"pl": symbolAfter,
+	"pt": symbolBeforeSpaced,
+	"ro": symbolAfter,
+	"ru": symbolAfter,
+	"sk": symbolAfter,
+	"sl": symbolAfter,
+	"sv": symbolAfter,
+	"uk": symbolAfter,
+}
+
+// regionalSymbolPositions overrides symbolPositions for specific regions.
+var regionalSymbolPositions = map[string]symbolPosition{
+	"de-CH": symbolBeforeSpaced,
+	"de-LI": symbolBeforeSpaced,
+	"pt-PT": symbolAfter,
+}
+
+// Format renders m for display in the given locale, for example
+// "₹8,312.50" for en-IN or "1.234,56 €" for de-DE. The amount is rounded
+// to the currency's minor units.
+func (m Money) Format(tag language.Tag) string {
+	printer := message.NewPrinter(tag)
+	formatted := formatDigits(printer, m.amount.Abs().StringFixed(MinorUnits(m.currency)))
+
+	symbol := m.currency
+	if unit, err := currency.ParseISO(m.currency); err == nil {
+		symbol = printer.Sprint(currency.Symbol(unit))
+	}
+
+	sign := ""
+	if m.amount.IsNegative() {
+		sign = "-"
+	}
+
+	switch positionFor(tag) {
+	case symbolAfter:
+		return sign + formatted + " " + symbol
+	case symbolBeforeSpaced:
+		return symbol + " " + sign + formatted
+	default:
+		// CLDR inserts a space when a symbol ends in a letter, as in "CHF 10.00".
+		runes := []rune(symbol)
+		if len(runes) > 0 && unicode.IsLetter(runes[len(runes)-1]) {
+			return sign + symbol + " " + formatted
+		}
+		return sign + symbol + formatted
+	}
+}
+
+// formatDigits localizes a plain decimal string such as "1234.50". The
+// integer and fraction parts are formatted as integers, so no digits are
+// lost to floating point, and joined with the locale's decimal mark.
+func formatDigits(printer *message.Printer, value string) string {
+	integer, fraction, _ := strings.Cut(value, ".")
+
+	formatted := integer
+	if n, err := strconv.ParseUint(integer, 10, 64); err == nil {
+		formatted = printer.Sprint(number.Decimal(n))
+	}
+
+	if fraction == "" {
+		return formatted
+	}
+
+	n, err := strconv.ParseUint(fraction, 10, 64)
+	if err != nil {
+		return formatted + "." + fraction
+	}
+
+	return formatted + decimalMark(printer) +
+		printer.Sprint(number.Decimal(n, number.MinIntegerDigits(len(fraction)), number.NoSeparator()))
+}
+
+// decimalMark returns the locale's decimal separator, found by formatting
+// 1.5 and trimming the localized digits.
+func decimalMark(printer *message.Printer) string {
+	sample := printer.Sprint(number.Decimal(1.5, number.Scale(1)))
+	one := printer.Sprint(number.Decimal(1))
+	five := printer.Sprint(number.Decimal(5))
+
+	return strings.TrimSuffix(strings.TrimPrefix(sample, one), five)
+}
+
+func positionFor(tag language.Tag) symbolPosition {
+	base, _ := tag.Base()
+	region, _ := tag.Region()
+
+	if position, ok := regionalSymbolPositions[base.String()+"-"+region.String()]; ok {
+		return position
+	}
+
+	if position, ok := symbolPositions[base.String()]; ok {
+		return position
+	}
+
+	return symbolBefore
+}
@@ -0,0 +1,280 @@
+package money
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/shopspring/decimal"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+)
+
+const defaultMinorUnits = 2
+
+var (
+	minorUnits = map[string]int32{
+		"JPY": 0,
+		"BTC": 8,
+	}
+	minorUnitsMu sync.RWMutex
+)
+
+// RegisterMinorUnits sets the number of decimal places used when rounding
+// amounts of currency. Unregistered currencies use two.
+func RegisterMinorUnits(currency string, units int32) {
+	minorUnitsMu.Lock()
+	defer minorUnitsMu.Unlock()
+
+	minorUnits[currency] = units
+}
+
+// MinorUnits returns the number of decimal places for currency.
+func MinorUnits(currency string) int32 {
+	minorUnitsMu.RLock()
+	defer minorUnitsMu.RUnlock()
+
+	if units, ok := minorUnits[currency]; ok {
+		return units
+	}
+
+	return defaultMinorUnits
+}
+
+// Money is an amount tied to the currency it is denominated in. Arithmetic
+// between different currencies fails instead of silently mixing them.
+type Money struct {
+	amount   decimal.Decimal
+	currency string
+}
+
+func New(amount decimal.Decimal, currency string) Money {
+	return Money{
+		amount:   amount,
+		currency: currency,
+	}
+}
+
+func Zero(currency string) Money {
+	return New(decimal.Zero, currency)
+}
+
+// Parse builds a Money from a decimal string such as "100.50".
+func Parse(amount, currency string) (Money, error) {
+	value, err := decimal.NewFromString(amount)
+	if err != nil {
+		return Money{}, appErrors.InvalidAmountError()
+	}
+
+	return New(value, currency), nil
+}
+
+func (m Money) Amount() decimal.Decimal {
+	return m.amount
+}
+
+func (m Money) Currency() string {
+	return m.currency
+}
+
+func (m Money) IsZero() bool {
+	return m.amount.IsZero()
+}
+
+func (m Money) IsPositive() bool {
+	return m.amount.IsPositive()
+}
+
+func (m Money) IsNegative() bool {
+	return m.amount.IsNegative()
+}
+
+func (m Money) sameCurrency(other Money) error {
+	if m.currency != other.currency {
+		return appErrors.CurrencyMismatchError(m.currency, other.currency)
+	}
+
+	return nil
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+
+	return New(m.amount.Add(other.amount), m.currency), nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+
+	return New(m.amount.Sub(other.amount), m.currency), nil
+}
+
+// Mul scales the amount, keeping the currency.
+func (m Money) Mul(factor decimal.Decimal) Money {
+	return New(m.amount.Mul(factor), m.currency)
+}
+
+// Exchange converts m into currency at rate units of currency per unit of m.
+// The result is not rounded.
+func (m Money) Exchange(currency string, rate decimal.Decimal) Money {
+	return New(m.amount.Mul(rate), currency)
+}
+
+// Round rounds half away from zero to the currency's minor units.
+func (m Money) Round() Money {
+	return New(m.amount.Round(MinorUnits(m.currency)), m.currency)
+}
+
+// RoundUp rounds towards positive infinity to the currency's minor units.
+func (m Money) RoundUp() Money {
+	return New(m.amount.RoundCeil(MinorUnits(m.currency)), m.currency)
+}
+
+// Cmp compares two amounts of the same currency, returning -1, 0 or 1.
+func (m Money) Cmp(other Money) (int, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return 0, err
+	}
+
+	return m.amount.Cmp(other.amount), nil
+}
+
+// Equal reports whether both currency and amount match.
+func (m Money) Equal(other Money) bool {
+	return m.currency == other.currency && m.amount.Equal(other.amount)
+}
+
+func (m Money) GreaterThan(other Money) (bool, error) {
+	cmp, err := m.Cmp(other)
+	return cmp > 0, err
+}
+
+func (m Money) LessThan(other Money) (bool, error) {
+	cmp, err := m.Cmp(other)
+	return cmp < 0, err
+}
+
+// Allocate splits m by ratios without losing minor units. The amount is
+// rounded first and any remainder is handed out one unit at a time to the
+// leading shares.
+func (m Money) Allocate(ratios ...int) ([]Money, error) {
+	if len(ratios) == 0 {
+		return nil, appErrors.InvalidAllocationError("at least one ratio is required")
+	}
+
+	total := 0
+	for _, ratio := range ratios {
+		if ratio < 0 {
+			return nil, appErrors.InvalidAllocationError("ratios must not be negative")
+		}
+		total += ratio
+	}
+	if total == 0 {
+		return nil, appErrors.InvalidAllocationError("ratios must not all be zero")
+	}
+
+	units := MinorUnits(m.currency)
+	unit := decimal.New(1, -units)
+	if m.amount.IsNegative() {
+		unit = unit.Neg()
+	}
+
+	rounded := m.Round().amount
+	remainder := rounded
+	shares := make([]Money, len(ratios))
+	for i, ratio := range ratios {
+		share := rounded.Mul(decimal.NewFromInt(int64(ratio))).Div(decimal.NewFromInt(int64(total))).Truncate(units)
+		shares[i] = New(share, m.currency)
+		remainder = remainder.Sub(share)
+	}
+
+	for i := 0; !remainder.IsZero(); i = (i + 1) % len(shares) {
+		if ratios[i] == 0 {
+			continue
+		}
+		shares[i].amount = shares[i].amount.Add(unit)
+		remainder = remainder.Sub(unit)
+	}
+
+	return shares, nil
+}
+
+// Split divides m into n parts that differ by at most one minor unit.
+func (m Money) Split(n int) ([]Money, error) {
+	if n <= 0 {
+		return nil, appErrors.InvalidAllocationError("number of parts must be positive")
+	}
+
+	ratios := make([]int, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+
+	return m.Allocate(ratios...)
+}
+
+// AmountString formats the amount with at least the currency's minor units,
+// keeping any extra precision the value carries.
+func (m Money) AmountString() string {
+	places := MinorUnits(m.currency)
+	if _, fraction, ok := strings.Cut(m.amount.String(), "."); ok && int32(len(fraction)) > places {
+		places = int32(len(fraction))
+	}
+
+	return m.amount.StringFixed(places)
+}
+
+func (m Money) String() string {
+	return m.AmountString() + " " + m.currency
+}
+
+type moneyJSON struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{
+		Amount:   m.AmountString(),
+		Currency: m.currency,
+	})
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var raw moneyJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	parsed, err := Parse(raw.Amount, raw.Currency)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
+
+// MarshalText encodes m as "<amount> <currency>", for example "100.00 USD".
+func (m Money) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *Money) UnmarshalText(text []byte) error {
+	amount, currency, ok := strings.Cut(strings.TrimSpace(string(text)), " ")
+	if !ok {
+		return fmt.Errorf("invalid money %q, expected \"<amount> <currency>\"", text)
+	}
+
+	parsed, err := Parse(amount, strings.TrimSpace(currency))
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
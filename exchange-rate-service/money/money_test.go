@@ -0,0 +1,235 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"golang.org/x/text/language"
+)
+
+func TestArithmetic(t *testing.T) {
+	a := New(decimal.RequireFromString("10.25"), "USD")
+	b := New(decimal.RequireFromString("4.75"), "USD")
+
+	sum, err := a.Add(b)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if sum.String() != "15.00 USD" {
+		t.Errorf("Add() = %s, expected 15.00 USD", sum)
+	}
+
+	diff, err := a.Sub(b)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if diff.String() != "5.50 USD" {
+		t.Errorf("Sub() = %s, expected 5.50 USD", diff)
+	}
+
+	greater, err := a.GreaterThan(b)
+	if err != nil || !greater {
+		t.Errorf("Expected %s > %s", a, b)
+	}
+}
+
+func TestCurrencyMismatch(t *testing.T) {
+	usd := New(decimal.NewFromInt(10), "USD")
+	eur := New(decimal.NewFromInt(10), "EUR")
+
+	if _, err := usd.Add(eur); err == nil {
+		t.Error("Expected error adding USD and EUR")
+	}
+	if _, err := usd.Sub(eur); err == nil {
+		t.Error("Expected error subtracting EUR from USD")
+	}
+	if _, err := usd.Cmp(eur); err == nil {
+		t.Error("Expected error comparing USD and EUR")
+	}
+	if usd.Equal(eur) {
+		t.Error("Amounts in different currencies should not be equal")
+	}
+}
+
+func TestRoundUsesMinorUnits(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		expected string
+	}{
+		{"8312.505", "INR", "8312.51"},
+		{"149.5", "JPY", "150"},
+		{"0.123456789", "BTC", "0.12345679"},
+	}
+
+	for _, tt := range tests {
+		m := New(decimal.RequireFromString(tt.amount), tt.currency).Round()
+		if m.AmountString() != tt.expected {
+			t.Errorf("Round(%s %s) = %s, expected %s", tt.amount, tt.currency, m.AmountString(), tt.expected)
+		}
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	m := New(decimal.RequireFromString("100.00"), "USD")
+
+	shares, err := m.Allocate(1, 1, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{"33.34", "33.33", "33.33"}
+	total := Zero("USD")
+	for i, share := range shares {
+		if share.AmountString() != expected[i] {
+			t.Errorf("Share %d = %s, expected %s", i, share.AmountString(), expected[i])
+		}
+		total, _ = total.Add(share)
+	}
+
+	if !total.Equal(m) {
+		t.Errorf("Shares add up to %s, expected %s", total, m)
+	}
+
+	shares, err = New(decimal.NewFromInt(5), "JPY").Allocate(70, 30)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if shares[0].AmountString() != "4" || shares[1].AmountString() != "1" {
+		t.Errorf("Allocate(70, 30) of 5 JPY = %s, %s", shares[0], shares[1])
+	}
+}
+
+func TestSplit(t *testing.T) {
+	m := New(decimal.RequireFromString("0.05"), "EUR")
+
+	parts, err := m.Split(3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{"0.02", "0.02", "0.01"}
+	for i, part := range parts {
+		if part.AmountString() != expected[i] {
+			t.Errorf("Part %d = %s, expected %s", i, part.AmountString(), expected[i])
+		}
+	}
+
+	if _, err := m.Split(0); err == nil {
+		t.Error("Expected error splitting into zero parts")
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	m := New(decimal.RequireFromString("8312.5"), "INR")
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(data) != `{"amount":"8312.50","currency":"INR"}` {
+		t.Errorf("MarshalJSON() = %s", data)
+	}
+
+	var decoded Money
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !decoded.Equal(m) {
+		t.Errorf("Decoded %s, expected %s", decoded, m)
+	}
+}
+
+func TestTextRoundTrip(t *testing.T) {
+	m := New(decimal.RequireFromString("100"), "USD")
+
+	text, err := m.MarshalText()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(text) != "100.00 USD" {
+		t.Errorf("MarshalText() = %s", text)
+	}
+
+	var decoded Money
+	if err := decoded.UnmarshalText(text); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !decoded.Equal(m) {
+		t.Errorf("Decoded %s, expected %s", decoded, m)
+	}
+
+	if err := decoded.UnmarshalText([]byte("100")); err == nil {
+		t.Error("Expected error for text without currency")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		locale   string
+		expected string
+	}{
+		{"8312.5", "INR", "en-IN", "₹8,312.50"},
+		{"1234567.5", "INR", "en-IN", "₹12,34,567.50"},
+		{"1234.56", "EUR", "de-DE", "1.234,56 €"},
+		{"1234.56", "USD", "en-US", "$1,234.56"},
+		{"12345", "JPY", "en-US", "¥12,345"},
+		{"0.5", "BTC", "en-US", "BTC 0.50000000"},
+		{"12345678.12345678", "BTC", "en-US", "BTC 12,345,678.12345678"},
+		{"0.00000001", "BTC", "de-DE", "0,00000001 BTC"},
+		{"1234567890123456", "JPY", "en-US", "¥1,234,567,890,123,456"},
+		{"-0.05", "EUR", "fr-FR", "-0,05 €"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale+"_"+tt.currency, func(t *testing.T) {
+			m := New(decimal.RequireFromString(tt.amount), tt.currency)
+			formatted := m.Format(language.MustParse(tt.locale))
+			if formatted != tt.expected {
+				t.Errorf("Format() = %q, expected %q", formatted, tt.expected)
+			}
+		})
+	}
+
+	if formatted := New(decimal.NewFromInt(5), "").Format(language.English); formatted != "5.00" {
+		t.Errorf("Format() without a currency = %q, expected 5.00", formatted)
+	}
+}
+
+func TestRounding(t *testing.T) {
+	value := decimal.RequireFromString("2.345")
+
+	tests := []struct {
+		mode      string
+		precision string
+		expected  string
+	}{
+		{"", "", "2.35"},
+		{"half_even", "", "2.34"},
+		{"up", "1", "2.4"},
+		{"down", "1", "2.3"},
+		{"half_up", "0", "2"},
+	}
+
+	for _, tt := range tests {
+		rounding, err := ParseRounding(tt.mode, tt.precision)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		m := New(value, "USD").RoundWith(rounding)
+		if !m.Amount().Equal(decimal.RequireFromString(tt.expected)) {
+			t.Errorf("RoundWith(%q, %q) = %s, expected %s", tt.mode, tt.precision, m.Amount(), tt.expected)
+		}
+	}
+
+	if _, err := ParseRounding("sideways", ""); err == nil {
+		t.Error("Expected error for unknown rounding mode")
+	}
+	if _, err := ParseRounding("", "-1"); err == nil {
+		t.Error("Expected error for negative precision")
+	}
+}
@@ -0,0 +1,78 @@
+package money
+
+import (
+	"strconv"
+
+	"github.com/shopspring/decimal"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+)
+
+type RoundingMode string
+
+const (
+	// RoundHalfUp rounds halves away from zero. It is the default.
+	RoundHalfUp   RoundingMode = "half_up"
+	RoundHalfEven RoundingMode = "half_even"
+	RoundUp       RoundingMode = "up"
+	RoundDown     RoundingMode = "down"
+)
+
+// Rounding controls how results are rounded. A nil Places means the
+// caller's default, such as the currency's minor units.
+type Rounding struct {
+	Mode   RoundingMode
+	Places *int32
+}
+
+// ParseRounding reads a rounding mode and a number of decimal places from
+// request parameters. Empty values keep the defaults.
+func ParseRounding(mode, precision string) (Rounding, error) {
+	var rounding Rounding
+
+	switch RoundingMode(mode) {
+	case "", RoundHalfUp, RoundHalfEven, RoundUp, RoundDown:
+		rounding.Mode = RoundingMode(mode)
+	default:
+		return Rounding{}, appErrors.InvalidRoundingError("unknown rounding mode " + mode)
+	}
+
+	if precision != "" {
+		places, err := strconv.ParseInt(precision, 10, 32)
+		if err != nil || places < 0 || places > 18 {
+			return Rounding{}, appErrors.InvalidRoundingError("precision must be between 0 and 18")
+		}
+		p := int32(places)
+		rounding.Places = &p
+	}
+
+	return rounding, nil
+}
+
+// IsZero reports whether r is unset, asking for no rounding at all.
+func (r Rounding) IsZero() bool {
+	return r.Mode == "" && r.Places == nil
+}
+
+// Apply rounds value to Places, or to defaultPlaces when Places is nil.
+func (r Rounding) Apply(value decimal.Decimal, defaultPlaces int32) decimal.Decimal {
+	places := defaultPlaces
+	if r.Places != nil {
+		places = *r.Places
+	}
+
+	switch r.Mode {
+	case RoundHalfEven:
+		return value.RoundBank(places)
+	case RoundUp:
+		return value.RoundCeil(places)
+	case RoundDown:
+		return value.RoundFloor(places)
+	default:
+		return value.Round(places)
+	}
+}
+
+// RoundWith rounds m using r, defaulting to the currency's minor units.
+func (m Money) RoundWith(r Rounding) Money {
+	return New(r.Apply(m.amount, MinorUnits(m.currency)), m.currency)
+}
@@ -0,0 +1,824 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: exchangerate/v1/exchange_rate.proto
+
+package exchangeratev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ConvertRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	From  string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To    string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	// Exactly one of amount and target_amount must be set.
+	Amount       string `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	TargetAmount string `protobuf:"bytes,4,opt,name=target_amount,json=targetAmount,proto3" json:"target_amount,omitempty"`
+	// YYYY-MM-DD; empty for the latest rates.
+	Date string `protobuf:"bytes,5,opt,name=date,proto3" json:"date,omitempty"`
+	// half_up (default), half_even, up or down.
+	Rounding      string `protobuf:"bytes,6,opt,name=rounding,proto3" json:"rounding,omitempty"`
+	Precision     *int32 `protobuf:"varint,7,opt,name=precision,proto3,oneof" json:"precision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConvertRequest) Reset() {
+	*x = ConvertRequest{}
+	mi := &file_exchangerate_v1_exchange_rate_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConvertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConvertRequest) ProtoMessage() {}
+
+func (x *ConvertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchangerate_v1_exchange_rate_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConvertRequest.ProtoReflect.Descriptor instead.
+func (*ConvertRequest) Descriptor() ([]byte, []int) {
+	return file_exchangerate_v1_exchange_rate_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ConvertRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *ConvertRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *ConvertRequest) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *ConvertRequest) GetTargetAmount() string {
+	if x != nil {
+		return x.TargetAmount
+	}
+	return ""
+}
+
+func (x *ConvertRequest) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *ConvertRequest) GetRounding() string {
+	if x != nil {
+		return x.Rounding
+	}
+	return ""
+}
+
+func (x *ConvertRequest) GetPrecision() int32 {
+	if x != nil && x.Precision != nil {
+		return *x.Precision
+	}
+	return 0
+}
+
+type RateSnapshot struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Provider  string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// YYYY-MM-DD for historical rates, empty for the latest ones.
+	Date          string `protobuf:"bytes,3,opt,name=date,proto3" json:"date,omitempty"`
+	Stale         bool   `protobuf:"varint,4,opt,name=stale,proto3" json:"stale,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateSnapshot) Reset() {
+	*x = RateSnapshot{}
+	mi := &file_exchangerate_v1_exchange_rate_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateSnapshot) ProtoMessage() {}
+
+func (x *RateSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_exchangerate_v1_exchange_rate_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateSnapshot.ProtoReflect.Descriptor instead.
+func (*RateSnapshot) Descriptor() ([]byte, []int) {
+	return file_exchangerate_v1_exchange_rate_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RateSnapshot) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *RateSnapshot) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *RateSnapshot) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *RateSnapshot) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+type Conversion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Amount        string                 `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Result        string                 `protobuf:"bytes,4,opt,name=result,proto3" json:"result,omitempty"`
+	Rate          string                 `protobuf:"bytes,5,opt,name=rate,proto3" json:"rate,omitempty"`
+	InverseRate   string                 `protobuf:"bytes,6,opt,name=inverse_rate,json=inverseRate,proto3" json:"inverse_rate,omitempty"`
+	Snapshot      *RateSnapshot          `protobuf:"bytes,7,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Conversion) Reset() {
+	*x = Conversion{}
+	mi := &file_exchangerate_v1_exchange_rate_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Conversion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Conversion) ProtoMessage() {}
+
+func (x *Conversion) ProtoReflect() protoreflect.Message {
+	mi := &file_exchangerate_v1_exchange_rate_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Conversion.ProtoReflect.Descriptor instead.
+func (*Conversion) Descriptor() ([]byte, []int) {
+	return file_exchangerate_v1_exchange_rate_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Conversion) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *Conversion) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *Conversion) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *Conversion) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *Conversion) GetRate() string {
+	if x != nil {
+		return x.Rate
+	}
+	return ""
+}
+
+func (x *Conversion) GetInverseRate() string {
+	if x != nil {
+		return x.InverseRate
+	}
+	return ""
+}
+
+func (x *Conversion) GetSnapshot() *RateSnapshot {
+	if x != nil {
+		return x.Snapshot
+	}
+	return nil
+}
+
+type BatchConvertRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*ConvertRequest      `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchConvertRequest) Reset() {
+	*x = BatchConvertRequest{}
+	mi := &file_exchangerate_v1_exchange_rate_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchConvertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchConvertRequest) ProtoMessage() {}
+
+func (x *BatchConvertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchangerate_v1_exchange_rate_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchConvertRequest.ProtoReflect.Descriptor instead.
+func (*BatchConvertRequest) Descriptor() ([]byte, []int) {
+	return file_exchangerate_v1_exchange_rate_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BatchConvertRequest) GetItems() []*ConvertRequest {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type Error struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One of the service's error codes, such as UNSUPPORTED_CURRENCY.
+	Code          string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_exchangerate_v1_exchange_rate_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_exchangerate_v1_exchange_rate_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_exchangerate_v1_exchange_rate_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Error) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type BatchResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Outcome:
+	//
+	//	*BatchResult_Conversion
+	//	*BatchResult_Error
+	Outcome       isBatchResult_Outcome `protobuf_oneof:"outcome"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchResult) Reset() {
+	*x = BatchResult{}
+	mi := &file_exchangerate_v1_exchange_rate_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchResult) ProtoMessage() {}
+
+func (x *BatchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_exchangerate_v1_exchange_rate_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchResult.ProtoReflect.Descriptor instead.
+func (*BatchResult) Descriptor() ([]byte, []int) {
+	return file_exchangerate_v1_exchange_rate_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BatchResult) GetOutcome() isBatchResult_Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return nil
+}
+
+func (x *BatchResult) GetConversion() *Conversion {
+	if x != nil {
+		if x, ok := x.Outcome.(*BatchResult_Conversion); ok {
+			return x.Conversion
+		}
+	}
+	return nil
+}
+
+func (x *BatchResult) GetError() *Error {
+	if x != nil {
+		if x, ok := x.Outcome.(*BatchResult_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isBatchResult_Outcome interface {
+	isBatchResult_Outcome()
+}
+
+type BatchResult_Conversion struct {
+	Conversion *Conversion `protobuf:"bytes,1,opt,name=conversion,proto3,oneof"`
+}
+
+type BatchResult_Error struct {
+	Error *Error `protobuf:"bytes,2,opt,name=error,proto3,oneof"`
+}
+
+func (*BatchResult_Conversion) isBatchResult_Outcome() {}
+
+func (*BatchResult_Error) isBatchResult_Outcome() {}
+
+type BatchConvertResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*BatchResult         `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchConvertResponse) Reset() {
+	*x = BatchConvertResponse{}
+	mi := &file_exchangerate_v1_exchange_rate_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchConvertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchConvertResponse) ProtoMessage() {}
+
+func (x *BatchConvertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_exchangerate_v1_exchange_rate_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchConvertResponse.ProtoReflect.Descriptor instead.
+func (*BatchConvertResponse) Descriptor() ([]byte, []int) {
+	return file_exchangerate_v1_exchange_rate_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BatchConvertResponse) GetResults() []*BatchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type GetRatesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Defaults to USD.
+	Base string `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
+	// Empty for every supported currency.
+	Symbols []string `protobuf:"bytes,2,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	// YYYY-MM-DD; empty for the latest rates.
+	Date          string `protobuf:"bytes,3,opt,name=date,proto3" json:"date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRatesRequest) Reset() {
+	*x = GetRatesRequest{}
+	mi := &file_exchangerate_v1_exchange_rate_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRatesRequest) ProtoMessage() {}
+
+func (x *GetRatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchangerate_v1_exchange_rate_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRatesRequest.ProtoReflect.Descriptor instead.
+func (*GetRatesRequest) Descriptor() ([]byte, []int) {
+	return file_exchangerate_v1_exchange_rate_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetRatesRequest) GetBase() string {
+	if x != nil {
+		return x.Base
+	}
+	return ""
+}
+
+func (x *GetRatesRequest) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+func (x *GetRatesRequest) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+type RateTable struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Base          string                 `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
+	Rates         map[string]string      `protobuf:"bytes,2,rep,name=rates,proto3" json:"rates,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Snapshot      *RateSnapshot          `protobuf:"bytes,3,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateTable) Reset() {
+	*x = RateTable{}
+	mi := &file_exchangerate_v1_exchange_rate_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateTable) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateTable) ProtoMessage() {}
+
+func (x *RateTable) ProtoReflect() protoreflect.Message {
+	mi := &file_exchangerate_v1_exchange_rate_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateTable.ProtoReflect.Descriptor instead.
+func (*RateTable) Descriptor() ([]byte, []int) {
+	return file_exchangerate_v1_exchange_rate_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RateTable) GetBase() string {
+	if x != nil {
+		return x.Base
+	}
+	return ""
+}
+
+func (x *RateTable) GetRates() map[string]string {
+	if x != nil {
+		return x.Rates
+	}
+	return nil
+}
+
+func (x *RateTable) GetSnapshot() *RateSnapshot {
+	if x != nil {
+		return x.Snapshot
+	}
+	return nil
+}
+
+type WatchRatesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Defaults to USD.
+	Base string `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
+	// Empty for every supported currency.
+	Symbols       []string `protobuf:"bytes,2,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRatesRequest) Reset() {
+	*x = WatchRatesRequest{}
+	mi := &file_exchangerate_v1_exchange_rate_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRatesRequest) ProtoMessage() {}
+
+func (x *WatchRatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_exchangerate_v1_exchange_rate_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRatesRequest.ProtoReflect.Descriptor instead.
+func (*WatchRatesRequest) Descriptor() ([]byte, []int) {
+	return file_exchangerate_v1_exchange_rate_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WatchRatesRequest) GetBase() string {
+	if x != nil {
+		return x.Base
+	}
+	return ""
+}
+
+func (x *WatchRatesRequest) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+var File_exchangerate_v1_exchange_rate_proto protoreflect.FileDescriptor
+
+const file_exchangerate_v1_exchange_rate_proto_rawDesc = "" +
+	"\n" +
+	"#exchangerate/v1/exchange_rate.proto\x12\x0fexchangerate.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xd2\x01\n" +
+	"\x0eConvertRequest\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\tR\x06amount\x12#\n" +
+	"\rtarget_amount\x18\x04 \x01(\tR\ftargetAmount\x12\x12\n" +
+	"\x04date\x18\x05 \x01(\tR\x04date\x12\x1a\n" +
+	"\brounding\x18\x06 \x01(\tR\brounding\x12!\n" +
+	"\tprecision\x18\a \x01(\x05H\x00R\tprecision\x88\x01\x01B\f\n" +
+	"\n" +
+	"_precision\"\x8f\x01\n" +
+	"\fRateSnapshot\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x129\n" +
+	"\n" +
+	"updated_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x12\n" +
+	"\x04date\x18\x03 \x01(\tR\x04date\x12\x14\n" +
+	"\x05stale\x18\x04 \x01(\bR\x05stale\"\xd2\x01\n" +
+	"\n" +
+	"Conversion\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\tR\x06amount\x12\x16\n" +
+	"\x06result\x18\x04 \x01(\tR\x06result\x12\x12\n" +
+	"\x04rate\x18\x05 \x01(\tR\x04rate\x12!\n" +
+	"\finverse_rate\x18\x06 \x01(\tR\vinverseRate\x129\n" +
+	"\bsnapshot\x18\a \x01(\v2\x1d.exchangerate.v1.RateSnapshotR\bsnapshot\"L\n" +
+	"\x13BatchConvertRequest\x125\n" +
+	"\x05items\x18\x01 \x03(\v2\x1f.exchangerate.v1.ConvertRequestR\x05items\"5\n" +
+	"\x05Error\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x87\x01\n" +
+	"\vBatchResult\x12=\n" +
+	"\n" +
+	"conversion\x18\x01 \x01(\v2\x1b.exchangerate.v1.ConversionH\x00R\n" +
+	"conversion\x12.\n" +
+	"\x05error\x18\x02 \x01(\v2\x16.exchangerate.v1.ErrorH\x00R\x05errorB\t\n" +
+	"\aoutcome\"N\n" +
+	"\x14BatchConvertResponse\x126\n" +
+	"\aresults\x18\x01 \x03(\v2\x1c.exchangerate.v1.BatchResultR\aresults\"S\n" +
+	"\x0fGetRatesRequest\x12\x12\n" +
+	"\x04base\x18\x01 \x01(\tR\x04base\x12\x18\n" +
+	"\asymbols\x18\x02 \x03(\tR\asymbols\x12\x12\n" +
+	"\x04date\x18\x03 \x01(\tR\x04date\"\xd1\x01\n" +
+	"\tRateTable\x12\x12\n" +
+	"\x04base\x18\x01 \x01(\tR\x04base\x12;\n" +
+	"\x05rates\x18\x02 \x03(\v2%.exchangerate.v1.RateTable.RatesEntryR\x05rates\x129\n" +
+	"\bsnapshot\x18\x03 \x01(\v2\x1d.exchangerate.v1.RateSnapshotR\bsnapshot\x1a8\n" +
+	"\n" +
+	"RatesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"A\n" +
+	"\x11WatchRatesRequest\x12\x12\n" +
+	"\x04base\x18\x01 \x01(\tR\x04base\x12\x18\n" +
+	"\asymbols\x18\x02 \x03(\tR\asymbols2\xd5\x02\n" +
+	"\x13ExchangeRateService\x12G\n" +
+	"\aConvert\x12\x1f.exchangerate.v1.ConvertRequest\x1a\x1b.exchangerate.v1.Conversion\x12[\n" +
+	"\fBatchConvert\x12$.exchangerate.v1.BatchConvertRequest\x1a%.exchangerate.v1.BatchConvertResponse\x12H\n" +
+	"\bGetRates\x12 .exchangerate.v1.GetRatesRequest\x1a\x1a.exchangerate.v1.RateTable\x12N\n" +
+	"\n" +
+	"WatchRates\x12\".exchangerate.v1.WatchRatesRequest\x1a\x1a.exchangerate.v1.RateTable0\x01BTZRgithub.com/yourusername/exchange-rate-service/proto/exchangerate/v1;exchangeratev1b\x06proto3"
+
+var (
+	file_exchangerate_v1_exchange_rate_proto_rawDescOnce sync.Once
+	file_exchangerate_v1_exchange_rate_proto_rawDescData []byte
+)
+
+func file_exchangerate_v1_exchange_rate_proto_rawDescGZIP() []byte {
+	file_exchangerate_v1_exchange_rate_proto_rawDescOnce.Do(func() {
+		file_exchangerate_v1_exchange_rate_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_exchangerate_v1_exchange_rate_proto_rawDesc), len(file_exchangerate_v1_exchange_rate_proto_rawDesc)))
+	})
+	return file_exchangerate_v1_exchange_rate_proto_rawDescData
+}
+
+var file_exchangerate_v1_exchange_rate_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_exchangerate_v1_exchange_rate_proto_goTypes = []any{
+	(*ConvertRequest)(nil),        // 0: exchangerate.v1.ConvertRequest
+	(*RateSnapshot)(nil),          // 1: exchangerate.v1.RateSnapshot
+	(*Conversion)(nil),            // 2: exchangerate.v1.Conversion
+	(*BatchConvertRequest)(nil),   // 3: exchangerate.v1.BatchConvertRequest
+	(*Error)(nil),                 // 4: exchangerate.v1.Error
+	(*BatchResult)(nil),           // 5: exchangerate.v1.BatchResult
+	(*BatchConvertResponse)(nil),  // 6: exchangerate.v1.BatchConvertResponse
+	(*GetRatesRequest)(nil),       // 7: exchangerate.v1.GetRatesRequest
+	(*RateTable)(nil),             // 8: exchangerate.v1.RateTable
+	(*WatchRatesRequest)(nil),     // 9: exchangerate.v1.WatchRatesRequest
+	nil,                           // 10: exchangerate.v1.RateTable.RatesEntry
+	(*timestamppb.Timestamp)(nil), // 11: google.protobuf.Timestamp
+}
+var file_exchangerate_v1_exchange_rate_proto_depIdxs = []int32{
+	11, // 0: exchangerate.v1.RateSnapshot.updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 1: exchangerate.v1.Conversion.snapshot:type_name -> exchangerate.v1.RateSnapshot
+	0,  // 2: exchangerate.v1.BatchConvertRequest.items:type_name -> exchangerate.v1.ConvertRequest
+	2,  // 3: exchangerate.v1.BatchResult.conversion:type_name -> exchangerate.v1.Conversion
+	4,  // 4: exchangerate.v1.BatchResult.error:type_name -> exchangerate.v1.Error
+	5,  // 5: exchangerate.v1.BatchConvertResponse.results:type_name -> exchangerate.v1.BatchResult
+	10, // 6: exchangerate.v1.RateTable.rates:type_name -> exchangerate.v1.RateTable.RatesEntry
+	1,  // 7: exchangerate.v1.RateTable.snapshot:type_name -> exchangerate.v1.RateSnapshot
+	0,  // 8: exchangerate.v1.ExchangeRateService.Convert:input_type -> exchangerate.v1.ConvertRequest
+	3,  // 9: exchangerate.v1.ExchangeRateService.BatchConvert:input_type -> exchangerate.v1.BatchConvertRequest
+	7,  // 10: exchangerate.v1.ExchangeRateService.GetRates:input_type -> exchangerate.v1.GetRatesRequest
+	9,  // 11: exchangerate.v1.ExchangeRateService.WatchRates:input_type -> exchangerate.v1.WatchRatesRequest
+	2,  // 12: exchangerate.v1.ExchangeRateService.Convert:output_type -> exchangerate.v1.Conversion
+	6,  // 13: exchangerate.v1.ExchangeRateService.BatchConvert:output_type -> exchangerate.v1.BatchConvertResponse
+	8,  // 14: exchangerate.v1.ExchangeRateService.GetRates:output_type -> exchangerate.v1.RateTable
+	8,  // 15: exchangerate.v1.ExchangeRateService.WatchRates:output_type -> exchangerate.v1.RateTable
+	12, // [12:16] is the sub-list for method output_type
+	8,  // [8:12] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_exchangerate_v1_exchange_rate_proto_init() }
+func file_exchangerate_v1_exchange_rate_proto_init() {
+	if File_exchangerate_v1_exchange_rate_proto != nil {
+		return
+	}
+	file_exchangerate_v1_exchange_rate_proto_msgTypes[0].OneofWrappers = []any{}
+	file_exchangerate_v1_exchange_rate_proto_msgTypes[5].OneofWrappers = []any{
+		(*BatchResult_Conversion)(nil),
+		(*BatchResult_Error)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_exchangerate_v1_exchange_rate_proto_rawDesc), len(file_exchangerate_v1_exchange_rate_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_exchangerate_v1_exchange_rate_proto_goTypes,
+		DependencyIndexes: file_exchangerate_v1_exchange_rate_proto_depIdxs,
+		MessageInfos:      file_exchangerate_v1_exchange_rate_proto_msgTypes,
+	}.Build()
+	File_exchangerate_v1_exchange_rate_proto = out.File
+	file_exchangerate_v1_exchange_rate_proto_goTypes = nil
+	file_exchangerate_v1_exchange_rate_proto_depIdxs = nil
+}
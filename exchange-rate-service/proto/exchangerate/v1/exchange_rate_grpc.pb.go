@@ -0,0 +1,259 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: exchangerate/v1/exchange_rate.proto
+
+package exchangeratev1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ExchangeRateService_Convert_FullMethodName      = "/exchangerate.v1.ExchangeRateService/Convert"
+	ExchangeRateService_BatchConvert_FullMethodName = "/exchangerate.v1.ExchangeRateService/BatchConvert"
+	ExchangeRateService_GetRates_FullMethodName     = "/exchangerate.v1.ExchangeRateService/GetRates"
+	ExchangeRateService_WatchRates_FullMethodName   = "/exchangerate.v1.ExchangeRateService/WatchRates"
+)
+
+// ExchangeRateServiceClient is the client API for ExchangeRateService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ExchangeRateService mirrors the HTTP API. Decimal amounts and rates are
+// strings so no precision is lost.
+type ExchangeRateServiceClient interface {
+	// Convert converts amount of from into to, or with target_amount returns
+	// the amount of from needed to receive it.
+	Convert(ctx context.Context, in *ConvertRequest, opts ...grpc.CallOption) (*Conversion, error)
+	// BatchConvert converts every item independently. Failed items carry an
+	// error instead of failing the call.
+	BatchConvert(ctx context.Context, in *BatchConvertRequest, opts ...grpc.CallOption) (*BatchConvertResponse, error)
+	// GetRates returns the rate table re-based to base.
+	GetRates(ctx context.Context, in *GetRatesRequest, opts ...grpc.CallOption) (*RateTable, error)
+	// WatchRates sends the current latest rate table, then a new one after
+	// every refresh.
+	WatchRates(ctx context.Context, in *WatchRatesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RateTable], error)
+}
+
+type exchangeRateServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewExchangeRateServiceClient(cc grpc.ClientConnInterface) ExchangeRateServiceClient {
+	return &exchangeRateServiceClient{cc}
+}
+
+func (c *exchangeRateServiceClient) Convert(ctx context.Context, in *ConvertRequest, opts ...grpc.CallOption) (*Conversion, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Conversion)
+	err := c.cc.Invoke(ctx, ExchangeRateService_Convert_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exchangeRateServiceClient) BatchConvert(ctx context.Context, in *BatchConvertRequest, opts ...grpc.CallOption) (*BatchConvertResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchConvertResponse)
+	err := c.cc.Invoke(ctx, ExchangeRateService_BatchConvert_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exchangeRateServiceClient) GetRates(ctx context.Context, in *GetRatesRequest, opts ...grpc.CallOption) (*RateTable, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RateTable)
+	err := c.cc.Invoke(ctx, ExchangeRateService_GetRates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exchangeRateServiceClient) WatchRates(ctx context.Context, in *WatchRatesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RateTable], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ExchangeRateService_ServiceDesc.Streams[0], ExchangeRateService_WatchRates_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRatesRequest, RateTable]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ExchangeRateService_WatchRatesClient = grpc.ServerStreamingClient[RateTable]
+
+// ExchangeRateServiceServer is the server API for ExchangeRateService service.
+// All implementations must embed UnimplementedExchangeRateServiceServer
+// for forward compatibility.
+//
+// ExchangeRateService mirrors the HTTP API. Decimal amounts and rates are
+// strings so no precision is lost.
+type ExchangeRateServiceServer interface {
+	// Convert converts amount of from into to, or with target_amount returns
+	// the amount of from needed to receive it.
+	Convert(context.Context, *ConvertRequest) (*Conversion, error)
+	// BatchConvert converts every item independently. Failed items carry an
+	// error instead of failing the call.
+	BatchConvert(context.Context, *BatchConvertRequest) (*BatchConvertResponse, error)
+	// GetRates returns the rate table re-based to base.
+	GetRates(context.Context, *GetRatesRequest) (*RateTable, error)
+	// WatchRates sends the current latest rate table, then a new one after
+	// every refresh.
+	WatchRates(*WatchRatesRequest, grpc.ServerStreamingServer[RateTable]) error
+	mustEmbedUnimplementedExchangeRateServiceServer()
+}
+
+// UnimplementedExchangeRateServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedExchangeRateServiceServer struct{}
+
+func (UnimplementedExchangeRateServiceServer) Convert(context.Context, *ConvertRequest) (*Conversion, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Convert not implemented")
+}
+func (UnimplementedExchangeRateServiceServer) BatchConvert(context.Context, *BatchConvertRequest) (*BatchConvertResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchConvert not implemented")
+}
+func (UnimplementedExchangeRateServiceServer) GetRates(context.Context, *GetRatesRequest) (*RateTable, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRates not implemented")
+}
+func (UnimplementedExchangeRateServiceServer) WatchRates(*WatchRatesRequest, grpc.ServerStreamingServer[RateTable]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchRates not implemented")
+}
+func (UnimplementedExchangeRateServiceServer) mustEmbedUnimplementedExchangeRateServiceServer() {}
+func (UnimplementedExchangeRateServiceServer) testEmbeddedByValue()                             {}
+
+// UnsafeExchangeRateServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ExchangeRateServiceServer will
+// result in compilation errors.
+type UnsafeExchangeRateServiceServer interface {
+	mustEmbedUnimplementedExchangeRateServiceServer()
+}
+
+func RegisterExchangeRateServiceServer(s grpc.ServiceRegistrar, srv ExchangeRateServiceServer) {
+	// If the following call pancis, it indicates UnimplementedExchangeRateServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ExchangeRateService_ServiceDesc, srv)
+}
+
+func _ExchangeRateService_Convert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConvertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangeRateServiceServer).Convert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExchangeRateService_Convert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangeRateServiceServer).Convert(ctx, req.(*ConvertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExchangeRateService_BatchConvert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchConvertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangeRateServiceServer).BatchConvert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExchangeRateService_BatchConvert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangeRateServiceServer).BatchConvert(ctx, req.(*BatchConvertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExchangeRateService_GetRates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangeRateServiceServer).GetRates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExchangeRateService_GetRates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangeRateServiceServer).GetRates(ctx, req.(*GetRatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExchangeRateService_WatchRates_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRatesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ExchangeRateServiceServer).WatchRates(m, &grpc.GenericServerStream[WatchRatesRequest, RateTable]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ExchangeRateService_WatchRatesServer = grpc.ServerStreamingServer[RateTable]
+
+// ExchangeRateService_ServiceDesc is the grpc.ServiceDesc for ExchangeRateService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ExchangeRateService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "exchangerate.v1.ExchangeRateService",
+	HandlerType: (*ExchangeRateServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Convert",
+			Handler:    _ExchangeRateService_Convert_Handler,
+		},
+		{
+			MethodName: "BatchConvert",
+			Handler:    _ExchangeRateService_BatchConvert_Handler,
+		},
+		{
+			MethodName: "GetRates",
+			Handler:    _ExchangeRateService_GetRates_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchRates",
+			Handler:       _ExchangeRateService_WatchRates_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "exchangerate/v1/exchange_rate.proto",
+}
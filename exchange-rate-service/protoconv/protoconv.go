@@ -0,0 +1,71 @@
+// Package protoconv converts service types to the exchangerate.v1 protobuf
+// messages shared by the gRPC API and protobuf HTTP responses.
+package protoconv
+
+import (
+	"errors"
+
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+	pb "github.com/yourusername/exchange-rate-service/proto/exchangerate/v1"
+	"github.com/yourusername/exchange-rate-service/service"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func Conversion(conversion *service.Conversion) *pb.Conversion {
+	return &pb.Conversion{
+		From:        conversion.Amount.Currency(),
+		To:          conversion.Result.Currency(),
+		Amount:      conversion.Amount.AmountString(),
+		Result:      conversion.Result.AmountString(),
+		Rate:        conversion.Rate.String(),
+		InverseRate: conversion.InverseRate.String(),
+		Snapshot:    Snapshot(conversion.Snapshot),
+	}
+}
+
+// BatchResult carries one batch item's conversion, or err when it failed.
+func BatchResult(conversion *service.Conversion, err error) *pb.BatchResult {
+	if err != nil {
+		return &pb.BatchResult{Outcome: &pb.BatchResult_Error{Error: Error(err)}}
+	}
+
+	return &pb.BatchResult{Outcome: &pb.BatchResult_Conversion{Conversion: Conversion(conversion)}}
+}
+
+func RateTable(table *service.RateTable) *pb.RateTable {
+	rates := make(map[string]string, len(table.Rates))
+	for symbol, rate := range table.Rates {
+		rates[symbol] = rate.String()
+	}
+
+	return &pb.RateTable{
+		Base:     table.Base,
+		Rates:    rates,
+		Snapshot: Snapshot(table.Snapshot),
+	}
+}
+
+func Snapshot(snapshot *service.RateSnapshot) *pb.RateSnapshot {
+	message := &pb.RateSnapshot{
+		Provider:  snapshot.Provider,
+		UpdatedAt: timestamppb.New(snapshot.UpdatedAt),
+		Stale:     snapshot.Stale,
+	}
+
+	if snapshot.Date != nil {
+		message.Date = snapshot.Date.Format("2006-01-02")
+	}
+
+	return message
+}
+
+// Error carries err's code and message. Errors other than CustomError are
+// reported as a generic internal error.
+func Error(err error) *pb.Error {
+	var customErr *appErrors.CustomError
+	if errors.As(err, &customErr) {
+		return &pb.Error{Code: string(customErr.Code), Message: customErr.Message}
+	}
+
+	return &pb.Error{Code: "INTERNAL", Message: "An unexpected error occured"}
+}
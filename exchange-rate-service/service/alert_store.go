@@ -0,0 +1,181 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+)
+
+type AlertCondition string
+
+const (
+	// AlertAbove fires when the rate crosses up to or above Threshold.
+	AlertAbove AlertCondition = "above"
+	// AlertBelow fires when the rate crosses down to or below Threshold.
+	AlertBelow AlertCondition = "below"
+	// AlertChangePct fires when the rate is at least Threshold percent away
+	// from the previous day's rate, in either direction.
+	AlertChangePct AlertCondition = "change_pct"
+)
+
+// AlertRule is a condition on a currency pair and the webhook notified when
+// it is met. Matches within Cooldown of the last notification are dropped.
+type AlertRule struct {
+	ID         string          `json:"id"`
+	Pair       Pair            `json:"pair"`
+	Condition  AlertCondition  `json:"condition"`
+	Threshold  decimal.Decimal `json:"threshold"`
+	Cooldown   time.Duration   `json:"cooldown"`
+	WebhookURL string          `json:"webhook_url"`
+
+	// Secret signs the webhook payloads. It is only shown when the rule is
+	// created.
+	Secret string `json:"secret"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// LastRate and LastTriggered carry evaluation state across refreshes
+	// and restarts.
+	LastRate      decimal.Decimal `json:"last_rate"`
+	LastTriggered time.Time       `json:"last_triggered"`
+}
+
+// AlertStore keeps alert rules in memory and, when it has a path, persists
+// every change to that file as JSON.
+type AlertStore struct {
+	path  string
+	rules map[string]*AlertRule
+	mu    sync.Mutex
+}
+
+// LoadAlertStore reads the rules saved at path. A missing file yields an
+// empty store, and an empty path a store that is never persisted.
+func LoadAlertStore(path string) (*AlertStore, error) {
+	store := &AlertStore{
+		path:  path,
+		rules: make(map[string]*AlertRule),
+	}
+
+	if path == "" {
+		return store, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []*AlertRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, err
+	}
+
+	for _, rule := range rules {
+		store.rules[rule.ID] = rule
+	}
+
+	return store, nil
+}
+
+// List returns copies of all rules, oldest first.
+func (s *AlertStore) List() []AlertRule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rules := make([]AlertRule, 0, len(s.rules))
+	for _, rule := range s.rules {
+		rules = append(rules, *rule)
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].CreatedAt.Equal(rules[j].CreatedAt) {
+			return rules[i].ID < rules[j].ID
+		}
+		return rules[i].CreatedAt.Before(rules[j].CreatedAt)
+	})
+
+	return rules
+}
+
+func (s *AlertStore) Get(id string) (AlertRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rule, ok := s.rules[id]
+	if !ok {
+		return AlertRule{}, appErrors.AlertNotFoundError(id)
+	}
+
+	return *rule, nil
+}
+
+// Put adds rule, or replaces the rule with the same ID, and saves the store.
+func (s *AlertStore) Put(rule AlertRule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rules[rule.ID] = &rule
+	return s.save()
+}
+
+func (s *AlertStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.rules[id]; !ok {
+		return appErrors.AlertNotFoundError(id)
+	}
+
+	delete(s.rules, id)
+	return s.save()
+}
+
+// Record applies fn to each rule still in the store and saves the store
+// once. It is used to write back evaluation state.
+func (s *AlertStore) Record(ids []string, fn func(rule *AlertRule)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range ids {
+		if rule, ok := s.rules[id]; ok {
+			fn(rule)
+		}
+	}
+
+	return s.save()
+}
+
+// save writes the rules to path with writeFileAtomic, so a crash never
+// leaves a partially written store. The caller holds s.mu.
+func (s *AlertStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	rules := make([]*AlertRule, 0, len(s.rules))
+	for _, rule := range s.rules {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+
+	data, err := json.MarshalIndent(rules, "", "  ")
+	if err != nil {
+		return appErrors.AlertStoreError(err)
+	}
+
+	if err := writeFileAtomic(s.path, data); err != nil {
+		return appErrors.AlertStoreError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+)
+
+func TestAlertStorePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.json")
+
+	store, err := LoadAlertStore(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rule := AlertRule{
+		ID:         "a1",
+		Pair:       Pair{From: "USD", To: "INR"},
+		Condition:  AlertAbove,
+		Threshold:  decimal.RequireFromString("84.5"),
+		Cooldown:   30 * time.Minute,
+		WebhookURL: "https://example.com/hook",
+		Secret:     "s3cret",
+	}
+	if err := store.Put(rule); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := store.Put(AlertRule{ID: "a2", Pair: Pair{From: "EUR", To: "USD"}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := store.Delete("a2"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	reloaded, err := LoadAlertStore(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rules := reloaded.List()
+	if len(rules) != 1 {
+		t.Fatalf("Expected 1 rule after reload, got %d", len(rules))
+	}
+	got := rules[0]
+	if got.Pair != rule.Pair || !got.Threshold.Equal(rule.Threshold) || got.Cooldown != rule.Cooldown || got.Secret != rule.Secret {
+		t.Errorf("Reloaded rule %+v, expected %+v", got, rule)
+	}
+
+	var customErr *appErrors.CustomError
+	if _, err := reloaded.Get("a2"); !errors.As(err, &customErr) || customErr.Code != appErrors.ErrAlertNotFound {
+		t.Errorf("Get(deleted) error = %v, expected %s", err, appErrors.ErrAlertNotFound)
+	}
+}
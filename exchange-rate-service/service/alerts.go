@@ -0,0 +1,383 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+)
+
+const (
+	defaultAlertRulesFile  = "alert_rules.json"
+	defaultDeadLetterFile  = "alert_dead_letters.jsonl"
+	defaultAlertCooldown   = time.Hour
+	alertQueueSize         = 100
+	alertDeliveryWorkers   = 4
+	deliveryQueueFullError = "delivery queue full"
+)
+
+// AlertRuleSpec is the user-supplied part of an alert rule, before
+// validation.
+type AlertRuleSpec struct {
+	Pair       string
+	Condition  string
+	Threshold  string
+	Cooldown   string
+	WebhookURL string
+}
+
+// AlertEvent is the JSON body POSTed to a rule's webhook when it fires.
+// ReferenceRate is the rate last seen for above and below rules, and the
+// previous day's rate for change_pct rules.
+type AlertEvent struct {
+	DeliveryID    string           `json:"delivery_id"`
+	RuleID        string           `json:"rule_id"`
+	Pair          Pair             `json:"pair"`
+	Condition     AlertCondition   `json:"condition"`
+	Threshold     decimal.Decimal  `json:"threshold"`
+	Rate          decimal.Decimal  `json:"rate"`
+	ReferenceRate *decimal.Decimal `json:"reference_rate,omitempty"`
+	ChangePct     *decimal.Decimal `json:"change_pct,omitempty"`
+	TriggeredAt   time.Time        `json:"triggered_at"`
+	Version       uint64           `json:"version"`
+}
+
+// AlertService manages alert rules, evaluates them against every refresh
+// of the latest rates and delivers matches to webhooks.
+type AlertService struct {
+	rateFetcher *RateFetcherService
+	store       *AlertStore
+	sender      *webhookSender
+	deliveries  chan webhookDelivery
+}
+
+func NewAlertService(rateFetcher *RateFetcherService) *AlertService {
+	rulesFile := defaultAlertRulesFile
+	if value, ok := os.LookupEnv("ALERT_RULES_FILE"); ok {
+		rulesFile = value
+	}
+
+	store, err := LoadAlertStore(rulesFile)
+	if err != nil {
+		panic(fmt.Sprintf("failed to load alert rules: %v", err))
+	}
+
+	deadLetterFile := defaultDeadLetterFile
+	if value, ok := os.LookupEnv("ALERT_DEAD_LETTER_FILE"); ok {
+		deadLetterFile = value
+	}
+
+	retries := defaultWebhookRetries
+	if value := os.Getenv("ALERT_WEBHOOK_RETRIES"); value != "" {
+		retries, err = strconv.Atoi(value)
+		if err != nil || retries < 0 {
+			panic(fmt.Sprintf("invalid ALERT_WEBHOOK_RETRIES: %s", value))
+		}
+	}
+
+	timeout := defaultWebhookTimeout
+	if value := os.Getenv("ALERT_WEBHOOK_TIMEOUT"); value != "" {
+		timeout, err = time.ParseDuration(value)
+		if err != nil {
+			panic(fmt.Sprintf("invalid ALERT_WEBHOOK_TIMEOUT: %v", err))
+		}
+	}
+
+	allowPrivate := false
+	if value := os.Getenv("ALERT_WEBHOOK_ALLOW_PRIVATE"); value != "" {
+		allowPrivate, err = strconv.ParseBool(value)
+		if err != nil {
+			panic(fmt.Sprintf("invalid ALERT_WEBHOOK_ALLOW_PRIVATE: %s", value))
+		}
+	}
+
+	return newAlertService(rateFetcher, store, &webhookSender{
+		client:         newWebhookClient(timeout, allowPrivate),
+		retries:        retries,
+		backoff:        webhookBackoff,
+		deadLetterPath: deadLetterFile,
+	})
+}
+
+func newAlertService(rateFetcher *RateFetcherService, store *AlertStore, sender *webhookSender) *AlertService {
+	return &AlertService{
+		rateFetcher: rateFetcher,
+		store:       store,
+		sender:      sender,
+		deliveries:  make(chan webhookDelivery, alertQueueSize),
+	}
+}
+
+// Start evaluates the rules after every refresh of the latest rates and
+// starts the webhook delivery workers.
+func (a *AlertService) Start() {
+	for i := 0; i < alertDeliveryWorkers; i++ {
+		go func() {
+			for delivery := range a.deliveries {
+				a.sender.deliver(delivery)
+			}
+		}()
+	}
+
+	updates, _ := a.rateFetcher.WatchRates()
+	go func() {
+		for snapshot := range updates {
+			if err := a.Evaluate(snapshot); err != nil {
+				log.Printf("Error evaluating alert rules: %v", err)
+			}
+		}
+	}()
+}
+
+func (a *AlertService) Rules() []AlertRule {
+	return a.store.List()
+}
+
+func (a *AlertService) Rule(id string) (AlertRule, error) {
+	return a.store.Get(id)
+}
+
+// CreateRule validates spec and saves it as a new rule with a generated ID
+// and webhook secret.
+func (a *AlertService) CreateRule(spec AlertRuleSpec) (AlertRule, error) {
+	now := time.Now().UTC()
+	rule := AlertRule{
+		ID:        randomHex(8),
+		Secret:    randomHex(32),
+		CreatedAt: now,
+	}
+
+	if err := a.apply(&rule, spec, now); err != nil {
+		return AlertRule{}, err
+	}
+
+	if err := a.store.Put(rule); err != nil {
+		return AlertRule{}, err
+	}
+
+	return rule, nil
+}
+
+// UpdateRule replaces the rule's settings with spec, keeping its ID and
+// secret. Evaluation starts afresh, as if the rule were new.
+func (a *AlertService) UpdateRule(id string, spec AlertRuleSpec) (AlertRule, error) {
+	rule, err := a.store.Get(id)
+	if err != nil {
+		return AlertRule{}, err
+	}
+
+	if err := a.apply(&rule, spec, time.Now().UTC()); err != nil {
+		return AlertRule{}, err
+	}
+	rule.LastRate = decimal.Zero
+	rule.LastTriggered = time.Time{}
+
+	if err := a.store.Put(rule); err != nil {
+		return AlertRule{}, err
+	}
+
+	return rule, nil
+}
+
+func (a *AlertService) DeleteRule(id string) error {
+	return a.store.Delete(id)
+}
+
+// DeadLetters returns the most recent deliveries that failed after every
+// retry.
+func (a *AlertService) DeadLetters() []DeadLetter {
+	return a.sender.DeadLetters()
+}
+
+func (a *AlertService) apply(rule *AlertRule, spec AlertRuleSpec, now time.Time) error {
+	pair, err := a.rateFetcher.ParsePair(spec.Pair)
+	if err != nil {
+		return err
+	}
+
+	condition := AlertCondition(spec.Condition)
+	switch condition {
+	case AlertAbove, AlertBelow, AlertChangePct:
+	default:
+		return appErrors.InvalidAlertRuleError("condition must be above, below or change_pct")
+	}
+
+	threshold, err := decimal.NewFromString(spec.Threshold)
+	if err != nil || !threshold.IsPositive() {
+		return appErrors.InvalidAlertRuleError("threshold must be a positive number")
+	}
+
+	cooldown := defaultAlertCooldown
+	if spec.Cooldown != "" {
+		cooldown, err = time.ParseDuration(spec.Cooldown)
+		if err != nil || cooldown < 0 {
+			return appErrors.InvalidAlertRuleError("cooldown must be a non-negative duration such as 30m")
+		}
+	}
+
+	webhook, err := url.Parse(spec.WebhookURL)
+	if err != nil || (webhook.Scheme != "http" && webhook.Scheme != "https") || webhook.Host == "" {
+		return appErrors.InvalidAlertRuleError("webhook_url must be an absolute http or https URL")
+	}
+
+	rule.Pair = pair
+	rule.Condition = condition
+	rule.Threshold = threshold
+	rule.Cooldown = cooldown
+	rule.WebhookURL = webhook.String()
+	rule.UpdatedAt = now
+
+	return nil
+}
+
+// Evaluate checks every rule against snapshot, queues a webhook delivery
+// for each rule that fires outside its cooldown, and saves the rates seen.
+// Rules fire when the rate moves past their threshold from the rate seen
+// last, so a rule's first evaluation only records the rate. Rules updated
+// while they were being evaluated keep their fresh state.
+func (a *AlertService) Evaluate(snapshot *RateSnapshot) error {
+	now := time.Now().UTC()
+	rules := a.store.List()
+
+	rates := make(map[string]decimal.Decimal, len(rules))
+	updated := make(map[string]time.Time, len(rules))
+	fired := make(map[string]bool)
+	previousDay := make(map[Pair]*decimal.Decimal)
+
+	for _, rule := range rules {
+		table, err := a.rateFetcher.RebaseSnapshot(rule.Pair.From, []string{rule.Pair.To}, snapshot)
+		if err != nil {
+			continue
+		}
+		rate := table.Rates[rule.Pair.To]
+		rates[rule.ID] = rate
+		updated[rule.ID] = rule.UpdatedAt
+
+		if rule.LastRate.IsZero() {
+			continue
+		}
+
+		event := AlertEvent{
+			RuleID:      rule.ID,
+			Pair:        rule.Pair,
+			Condition:   rule.Condition,
+			Threshold:   rule.Threshold,
+			Rate:        rate,
+			TriggeredAt: now,
+			Version:     snapshot.Version,
+		}
+
+		var matched bool
+		switch rule.Condition {
+		case AlertAbove:
+			matched = rate.GreaterThanOrEqual(rule.Threshold) && rule.LastRate.LessThan(rule.Threshold)
+			event.ReferenceRate = nonZero(rule.LastRate)
+		case AlertBelow:
+			matched = rate.LessThanOrEqual(rule.Threshold) && rule.LastRate.GreaterThan(rule.Threshold)
+			event.ReferenceRate = nonZero(rule.LastRate)
+		case AlertChangePct:
+			reference, ok := previousDay[rule.Pair]
+			if !ok {
+				reference = a.previousDayRate(rule.Pair, snapshot)
+				previousDay[rule.Pair] = reference
+			}
+			if reference == nil {
+				continue
+			}
+
+			change := changePct(rate, *reference)
+			matched = change.Abs().GreaterThanOrEqual(rule.Threshold) &&
+				changePct(rule.LastRate, *reference).Abs().LessThan(rule.Threshold)
+			event.ReferenceRate = reference
+			event.ChangePct = &change
+		}
+
+		if !matched || (!rule.LastTriggered.IsZero() && now.Sub(rule.LastTriggered) < rule.Cooldown) {
+			continue
+		}
+
+		fired[rule.ID] = true
+		event.DeliveryID = randomHex(8)
+		a.enqueue(rule, event)
+	}
+
+	ids := make([]string, 0, len(rates))
+	for id := range rates {
+		ids = append(ids, id)
+	}
+
+	return a.store.Record(ids, func(rule *AlertRule) {
+		if !rule.UpdatedAt.Equal(updated[rule.ID]) {
+			return
+		}
+
+		rule.LastRate = rates[rule.ID]
+		if fired[rule.ID] {
+			rule.LastTriggered = now
+		}
+	})
+}
+
+// changePct returns how far rate is from reference, as a percentage of
+// reference.
+func changePct(rate, reference decimal.Decimal) decimal.Decimal {
+	return rate.Sub(reference).Div(reference).Mul(hundred).Round(4)
+}
+
+// previousDayRate returns the pair's rate on the day before snapshot, or
+// nil if it is unavailable.
+func (a *AlertService) previousDayRate(pair Pair, snapshot *RateSnapshot) *decimal.Decimal {
+	day := snapshot.UpdatedAt.UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+
+	table, err := a.rateFetcher.GetRates(pair.From, []string{pair.To}, &day)
+	if err != nil {
+		log.Printf("Error loading %s rate for %s: %v", pair, day.Format("2006-01-02"), err)
+		return nil
+	}
+
+	return nonZero(table.Rates[pair.To])
+}
+
+// enqueue hands the event to the delivery workers, dead-lettering it if
+// they are too far behind.
+func (a *AlertService) enqueue(rule AlertRule, event AlertEvent) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	delivery := webhookDelivery{
+		ID:      event.DeliveryID,
+		RuleID:  rule.ID,
+		URL:     rule.WebhookURL,
+		Secret:  rule.Secret,
+		Payload: payload,
+	}
+
+	select {
+	case a.deliveries <- delivery:
+	default:
+		a.sender.deadLetter(DeadLetter{
+			DeliveryID: delivery.ID,
+			RuleID:     delivery.RuleID,
+			URL:        delivery.URL,
+			Error:      deliveryQueueFullError,
+			FailedAt:   time.Now().UTC(),
+			Payload:    payload,
+		})
+	}
+}
+
+func nonZero(value decimal.Decimal) *decimal.Decimal {
+	if value.IsZero() {
+		return nil
+	}
+
+	return &value
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+)
+
+// newTestAlertService returns an alert service without delivery workers;
+// tests send its queued deliveries with deliverQueued.
+func newTestAlertService(t *testing.T, client *http.Client) (*AlertService, string) {
+	t.Helper()
+
+	deadLetters := filepath.Join(t.TempDir(), "dead_letters.jsonl")
+	store, err := LoadAlertStore("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	s := newTestRateFetcher(t, "USD,EUR,INR")
+	return newAlertService(s, store, &webhookSender{
+		client:         client,
+		retries:        2,
+		backoff:        time.Millisecond,
+		deadLetterPath: deadLetters,
+	}), deadLetters
+}
+
+// deliverQueued sends every queued delivery.
+func deliverQueued(a *AlertService) {
+	for {
+		select {
+		case delivery := <-a.deliveries:
+			a.sender.deliver(delivery)
+		default:
+			return
+		}
+	}
+}
+
+func testSnapshot(inr string) *RateSnapshot {
+	return &RateSnapshot{
+		Rates: map[string]decimal.Decimal{
+			"USD": decimal.NewFromInt(1),
+			"EUR": decimal.RequireFromString("0.8"),
+			"INR": decimal.RequireFromString(inr),
+		},
+		UpdatedAt: time.Now().UTC(),
+	}
+}
+
+func TestAlertRuleValidation(t *testing.T) {
+	a, _ := newTestAlertService(t, http.DefaultClient)
+
+	valid := AlertRuleSpec{Pair: "usd/inr", Condition: "above", Threshold: "84", WebhookURL: "https://example.com/hook"}
+	rule, err := a.CreateRule(valid)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rule.Pair.String() != "USD/INR" || rule.Cooldown != defaultAlertCooldown || rule.ID == "" || rule.Secret == "" {
+		t.Errorf("CreateRule() = %+v", rule)
+	}
+
+	tests := []struct {
+		name string
+		edit func(*AlertRuleSpec)
+		code appErrors.ErrorCode
+	}{
+		{"pair", func(s *AlertRuleSpec) { s.Pair = "USDINR" }, appErrors.ErrInvalidPair},
+		{"condition", func(s *AlertRuleSpec) { s.Condition = "crosses" }, appErrors.ErrInvalidAlertRule},
+		{"threshold", func(s *AlertRuleSpec) { s.Threshold = "-1" }, appErrors.ErrInvalidAlertRule},
+		{"cooldown", func(s *AlertRuleSpec) { s.Cooldown = "soon" }, appErrors.ErrInvalidAlertRule},
+		{"webhook", func(s *AlertRuleSpec) { s.WebhookURL = "ftp://example.com" }, appErrors.ErrInvalidAlertRule},
+	}
+
+	for _, tt := range tests {
+		spec := valid
+		tt.edit(&spec)
+
+		_, err := a.CreateRule(spec)
+		var customErr *appErrors.CustomError
+		if !errors.As(err, &customErr) || customErr.Code != tt.code {
+			t.Errorf("%s: error = %v, expected %s", tt.name, err, tt.code)
+		}
+	}
+}
+
+func TestAlertCrossingDeliversSignedWebhook(t *testing.T) {
+	events := make(chan AlertEvent, 10)
+	var secret atomic.Value
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+		if r.Header.Get(SignatureHeader) != SignWebhook(secret.Load().(string), timestamp, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var event AlertEvent
+		json.Unmarshal(body, &event)
+		events <- event
+	}))
+	defer receiver.Close()
+
+	a, _ := newTestAlertService(t, receiver.Client())
+	rule, err := a.CreateRule(AlertRuleSpec{Pair: "USD/INR", Condition: "above", Threshold: "84", Cooldown: "0s", WebhookURL: receiver.URL})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	secret.Store(rule.Secret)
+
+	for _, inr := range []string{"83.5", "84.2", "84.6", "83.9", "84.1"} {
+		if err := a.Evaluate(testSnapshot(inr)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		deliverQueued(a)
+	}
+
+	close(events)
+	var rates []string
+	for event := range events {
+		if event.RuleID != rule.ID || event.Pair.String() != "USD/INR" {
+			t.Errorf("Unexpected event %+v", event)
+		}
+		rates = append(rates, event.Rate.String())
+	}
+
+	if len(rates) != 2 || rates[0] != "84.2" || rates[1] != "84.1" {
+		t.Errorf("Delivered rates %v, expected [84.2 84.1]", rates)
+	}
+
+	if len(a.DeadLetters()) != 0 {
+		t.Errorf("Expected no dead letters, got %v", a.DeadLetters())
+	}
+}
+
+func TestAlertCooldownAndDailyChange(t *testing.T) {
+	var deliveries atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deliveries.Add(1)
+	}))
+	defer receiver.Close()
+
+	a, _ := newTestAlertService(t, receiver.Client())
+	yesterday := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	a.rateFetcher.cache.SetHistoricalRates(yesterday, map[string]decimal.Decimal{
+		"USD": decimal.NewFromInt(1),
+		"EUR": decimal.RequireFromString("0.8"),
+		"INR": decimal.NewFromInt(80),
+	})
+
+	if _, err := a.CreateRule(AlertRuleSpec{Pair: "USD/INR", Condition: "change_pct", Threshold: "1", Cooldown: "1h", WebhookURL: receiver.URL}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// 0.5% is under the threshold; 2% fires once, and moving back past
+	// the threshold within the cooldown stays silent.
+	for _, inr := range []string{"80.4", "81.6", "80.5", "81.8"} {
+		if err := a.Evaluate(testSnapshot(inr)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		deliverQueued(a)
+	}
+
+	if deliveries.Load() != 1 {
+		t.Errorf("Expected 1 delivery, got %d", deliveries.Load())
+	}
+}
+
+func TestAlertFiresOnlyOnTransitions(t *testing.T) {
+	var deliveries atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deliveries.Add(1)
+	}))
+	defer receiver.Close()
+
+	yesterday := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+
+	tests := []struct {
+		condition string
+		threshold string
+		rates     []string
+		expected  int32
+	}{
+		// The first evaluation is past the threshold but has nothing to
+		// cross from, so only the later crossing fires.
+		{"above", "84", []string{"84.5", "84.6", "83.9", "84.1"}, 1},
+		{"below", "84", []string{"83.5", "83.4", "84.1", "83.9"}, 1},
+		// Staying 2% away from yesterday's 80 fires once, not once per
+		// evaluation, even without a cooldown.
+		{"change_pct", "1", []string{"81.6", "81.8", "80.2", "81.7", "81.9"}, 1},
+	}
+
+	for _, tt := range tests {
+		deliveries.Store(0)
+		a, _ := newTestAlertService(t, receiver.Client())
+		a.rateFetcher.cache.SetHistoricalRates(yesterday, map[string]decimal.Decimal{
+			"USD": decimal.NewFromInt(1),
+			"EUR": decimal.RequireFromString("0.8"),
+			"INR": decimal.NewFromInt(80),
+		})
+
+		if _, err := a.CreateRule(AlertRuleSpec{Pair: "USD/INR", Condition: tt.condition, Threshold: tt.threshold, Cooldown: "0s", WebhookURL: receiver.URL}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		for _, inr := range tt.rates {
+			if err := a.Evaluate(testSnapshot(inr)); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			deliverQueued(a)
+		}
+
+		if n := deliveries.Load(); n != tt.expected {
+			t.Errorf("%s %v: %d deliveries, expected %d", tt.condition, tt.rates, n, tt.expected)
+		}
+	}
+}
+
+func TestAlertWebhookRetriesThenDeadLetters(t *testing.T) {
+	var attempts atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer receiver.Close()
+
+	a, deadLetterPath := newTestAlertService(t, receiver.Client())
+	if _, err := a.CreateRule(AlertRuleSpec{Pair: "USD/INR", Condition: "below", Threshold: "85", WebhookURL: receiver.URL}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The first evaluation only records the rate; the second crosses it.
+	a.Evaluate(testSnapshot("86"))
+	a.Evaluate(testSnapshot("84"))
+	deliverQueued(a)
+
+	if attempts.Load() != 3 || len(a.DeadLetters()) != 0 {
+		t.Fatalf("Expected success on attempt 3, got %d attempts and %d dead letters", attempts.Load(), len(a.DeadLetters()))
+	}
+
+	receiver.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	if _, err := a.CreateRule(AlertRuleSpec{Pair: "EUR/INR", Condition: "above", Threshold: "100", WebhookURL: receiver.URL}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// EUR/INR crosses from 98.75 to 105.
+	a.Evaluate(testSnapshot("79"))
+	a.Evaluate(testSnapshot("84"))
+	deliverQueued(a)
+
+	letters := a.DeadLetters()
+	if len(letters) != 1 || letters[0].Attempts != 3 {
+		t.Fatalf("Expected 1 dead letter after 3 attempts, got %+v", letters)
+	}
+
+	data, err := os.ReadFile(deadLetterPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var letter DeadLetter
+	if err := json.Unmarshal(data, &letter); err != nil || letter.DeliveryID != letters[0].DeliveryID {
+		t.Errorf("Dead-letter file has %s", data)
+	}
+}
+
+func TestWebhookClientRefusesPrivateAddresses(t *testing.T) {
+	for _, address := range []string{"127.0.0.1:80", "[::1]:443", "10.1.2.3:80", "192.168.0.10:80", "169.254.169.254:80", "100.64.0.1:80", "0.0.0.0:80", "[::ffff:127.0.0.1]:80", "[fd00::1]:443"} {
+		if err := refusePrivateAddress("tcp", address, nil); err == nil {
+			t.Errorf("Expected %s to be refused", address)
+		}
+	}
+
+	for _, address := range []string{"93.184.215.14:443", "[2606:2800:21f:cb07:6820:80da:af6b:8b2c]:443"} {
+		if err := refusePrivateAddress("tcp", address, nil); err != nil {
+			t.Errorf("Expected %s to be allowed, got %v", address, err)
+		}
+	}
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer receiver.Close()
+
+	if _, err := newWebhookClient(time.Second, false).Get(receiver.URL); err == nil {
+		t.Error("Expected a loopback webhook to be refused")
+	}
+	resp, err := newWebhookClient(time.Second, true).Get(receiver.URL)
+	if err != nil {
+		t.Fatalf("Expected private webhooks to be allowed when configured, got %v", err)
+	}
+	resp.Body.Close()
+}
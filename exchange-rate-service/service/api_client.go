@@ -1,16 +1,22 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/shopspring/decimal"
 	appErrors "github.com/yourusername/exchange-rate-service/errors"
+	"github.com/yourusername/exchange-rate-service/metrics"
 )
 
 type LatestAPIResponse struct {
@@ -25,9 +31,15 @@ type HistoricalAPIResponse struct {
 	Quotes  map[string]decimal.Decimal `json:"quotes"`
 }
 
+const defaultAPITimeout = 10 * time.Second
+
 type APIClient struct {
 	apiKey  string
 	baseURL string
+	client  *http.Client
+
+	state ProviderState
+	mu    sync.Mutex
 }
 
 func NewClient() *APIClient {
@@ -36,22 +48,67 @@ func NewClient() *APIClient {
 	if apiKey == "" {
 		panic("API_KEY not set in environment")
 	}
-	return &APIClient{
+
+	timeout := defaultAPITimeout
+	if value := os.Getenv("API_TIMEOUT"); value != "" {
+		var err error
+		timeout, err = time.ParseDuration(value)
+		if err != nil {
+			panic(fmt.Sprintf("invalid API_TIMEOUT: %v", err))
+		}
+	}
+
+	client := &APIClient{
 		apiKey:  apiKey,
 		baseURL: "https://api.exchangerate.host",
+		client:  &http.Client{Timeout: timeout},
+	}
+	client.state.Name = client.Name()
+
+	return client
+}
+
+// Name identifies the rate provider in responses.
+func (c *APIClient) Name() string {
+	return "exchangerate.host"
+}
+
+// State reports the outcome of the most recent upstream calls.
+func (c *APIClient) State() ProviderState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.state
+}
+
+// record updates the metrics after a call to endpoint that started at
+// start. Only latest-rate calls update the provider state: historical
+// calls fail for dates the provider has no rates for, which says nothing
+// about its health.
+func (c *APIClient) record(endpoint string, start time.Time, err error) {
+	metrics.ObserveUpstream(c.Name(), endpoint, start, err)
+	if endpoint != "live" {
+		return
 	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.state.record(err, time.Now())
 }
 
-func (c *APIClient) FetchLatestRates() (map[string]decimal.Decimal, error) {
+func (c *APIClient) FetchLatestRates() (rates map[string]decimal.Decimal, err error) {
+	start := time.Now()
+	defer func() { c.record("live", start, err) }()
 
 	u, _ := url.Parse(c.baseURL + "/live")
 	q := u.Query()
 	q.Set("access_key", c.apiKey)
 	u.RawQuery = q.Encode()
 
-	resp, err := http.Get(u.String())
+	resp, err := c.client.Get(u.String())
 	if err != nil {
-		return nil, appErrors.APIFetchError(err)
+		return nil, fetchError(err)
 	}
 	defer resp.Body.Close()
 
@@ -61,6 +118,9 @@ func (c *APIClient) FetchLatestRates() (map[string]decimal.Decimal, error) {
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
+		if isTimeout(err) {
+			return nil, appErrors.UpstreamTimeoutError(err)
+		}
 		return nil, appErrors.APIResponseError(err)
 	}
 
@@ -90,17 +150,20 @@ func (c *APIClient) FetchLatestRates() (map[string]decimal.Decimal, error) {
 	return normalized, nil
 }
 
-func (c *APIClient) FetchHistoricalRates(date time.Time) (map[string]decimal.Decimal, error) {
+func (c *APIClient) FetchHistoricalRates(date time.Time) (rates map[string]decimal.Decimal, err error) {
+	start := time.Now()
+	defer func() { c.record("historical", start, err) }()
+
 	u, _ := url.Parse(c.baseURL + "/historical")
 	q := u.Query()
 	q.Set("access_key", c.apiKey)
 	q.Set("date", date.Format("2006-01-02"))
 	u.RawQuery = q.Encode()
 
-	resp, err := http.Get(u.String())
+	resp, err := c.client.Get(u.String())
 
 	if err != nil {
-		return nil, appErrors.APIFetchError(err)
+		return nil, fetchError(err)
 	}
 
 	defer resp.Body.Close()
@@ -111,6 +174,9 @@ func (c *APIClient) FetchHistoricalRates(date time.Time) (map[string]decimal.Dec
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
+		if isTimeout(err) {
+			return nil, appErrors.UpstreamTimeoutError(err)
+		}
 		return nil, appErrors.APIResponseError(err)
 	}
 
@@ -139,3 +205,18 @@ func (c *APIClient) FetchHistoricalRates(date time.Time) (map[string]decimal.Dec
 
 	return normalized, nil
 }
+
+// fetchError classifies a failed request to the provider as a timeout or a
+// connection failure.
+func fetchError(err error) error {
+	if isTimeout(err) {
+		return appErrors.UpstreamTimeoutError(err)
+	}
+
+	return appErrors.APIFetchError(err)
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
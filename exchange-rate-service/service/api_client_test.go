@@ -0,0 +1,56 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+)
+
+func TestFetchErrorsAreClassified(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		code    appErrors.ErrorCode
+	}{
+		{"timeout", func(w http.ResponseWriter, r *http.Request) { time.Sleep(200 * time.Millisecond) }, appErrors.ErrUpstreamTimeout},
+		{"bad status", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusBadGateway) }, appErrors.ErrAPIBadStatus},
+		{"unsuccessful", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"success": false, "error": {"info": "usage limit reached"}}`))
+		}, appErrors.ErrAPIBadResponse},
+	}
+
+	for _, tt := range tests {
+		upstream := httptest.NewServer(tt.handler)
+		client := &APIClient{baseURL: upstream.URL, client: &http.Client{Timeout: 50 * time.Millisecond}}
+
+		_, err := client.FetchLatestRates()
+		upstream.Close()
+
+		var customErr *appErrors.CustomError
+		if !errors.As(err, &customErr) || customErr.Code != tt.code {
+			t.Errorf("%s: error %v, expected %s", tt.name, err, tt.code)
+		}
+	}
+}
+
+func TestUnavailableWrapsOnlyFetchFailures(t *testing.T) {
+	for _, err := range []error{
+		appErrors.UpstreamTimeoutError(nil),
+		appErrors.APIBadStatusError(http.StatusInternalServerError),
+		appErrors.APIResponseError(nil),
+	} {
+		if unavailable(err) != err {
+			t.Errorf("Expected %v to be reported as is", err)
+		}
+	}
+
+	err := unavailable(appErrors.APIFetchError(nil))
+	if !errors.Is(err, &appErrors.CustomError{Code: appErrors.ErrRatesUnavailable}) ||
+		!errors.Is(err, &appErrors.CustomError{Code: appErrors.ErrAPIFetchFailed}) {
+		t.Errorf("Expected RATES_UNAVAILABLE wrapping API_FETCH_FAILED, got %v", err)
+	}
+}
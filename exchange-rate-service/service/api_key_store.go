@@ -0,0 +1,268 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+)
+
+// Scope is a group of endpoints an API key may call.
+type Scope string
+
+const (
+	ScopeConvert Scope = "convert"
+	ScopeRates   Scope = "rates"
+	// ScopeAdmin covers alert rules and key management, and grants every
+	// other scope.
+	ScopeAdmin Scope = "admin"
+)
+
+// Scopes lists every scope in the order they are documented.
+var Scopes = []Scope{ScopeConvert, ScopeRates, ScopeAdmin}
+
+// APIKey is a client credential. Only the SHA-256 hash of the key is kept;
+// the key itself is shown once, when it is issued or rotated.
+type APIKey struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	Hash   string  `json:"hash"`
+	Prefix string  `json:"prefix"`
+	Scopes []Scope `json:"scopes"`
+
+	// DailyQuota is the number of requests allowed per UTC day; zero is
+	// unlimited. A zero ExpiresAt never expires.
+	DailyQuota int       `json:"daily_quota"`
+	ExpiresAt  time.Time `json:"expires_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+	RotatedAt time.Time `json:"rotated_at"`
+	RevokedAt time.Time `json:"revoked_at"`
+
+	Usage KeyUsage `json:"usage"`
+}
+
+// KeyUsage counts the requests made with a key. Today counts the requests
+// on Day, a UTC date.
+type KeyUsage struct {
+	Day      string    `json:"day"`
+	Today    int       `json:"today"`
+	Total    int64     `json:"total"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// HasScope reports whether the key grants scope.
+func (k APIKey) HasScope(scope Scope) bool {
+	for _, granted := range k.Scopes {
+		if granted == scope || granted == ScopeAdmin {
+			return true
+		}
+	}
+
+	return false
+}
+
+// UsedOn returns the number of requests made on the UTC day of now.
+func (k APIKey) UsedOn(now time.Time) int {
+	if k.Usage.Day != now.UTC().Format("2006-01-02") {
+		return 0
+	}
+
+	return k.Usage.Today
+}
+
+// APIKeyStore keeps API keys in memory, indexed by hash, and, when it has a
+// path, persists them to that file as JSON. Key changes are saved at once;
+// usage is saved by Flush.
+type APIKeyStore struct {
+	path   string
+	keys   map[string]*APIKey
+	byHash map[string]string
+	dirty  bool
+	mu     sync.Mutex
+}
+
+// LoadAPIKeyStore reads the keys saved at path. A missing file yields an
+// empty store, and an empty path a store that is never persisted.
+func LoadAPIKeyStore(path string) (*APIKeyStore, error) {
+	store := &APIKeyStore{
+		path:   path,
+		keys:   make(map[string]*APIKey),
+		byHash: make(map[string]string),
+	}
+
+	if path == "" {
+		return store, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []*APIKey
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, err
+	}
+
+	for _, key := range keys {
+		store.keys[key.ID] = key
+		store.byHash[key.Hash] = key.ID
+	}
+
+	return store, nil
+}
+
+// List returns copies of all keys, oldest first.
+func (s *APIKeyStore) List() []APIKey {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]APIKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, *key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].ID < keys[j].ID
+		}
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+
+	return keys
+}
+
+func (s *APIKeyStore) Get(id string) (APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.keys[id]
+	if !ok {
+		return APIKey{}, appErrors.APIKeyNotFoundError(id)
+	}
+
+	return *key, nil
+}
+
+// Put adds key, or replaces the key with the same ID, and saves the store.
+// If the save fails the store is left as it was.
+func (s *APIKeyStore) Put(key APIKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.replace(s.keys[key.ID], &key)
+}
+
+// Update applies fn to key id and saves the store, returning the updated
+// key. Unlike a Get followed by a Put, no usage recorded in between is
+// lost. If fn fails or the save fails the store is left as it was.
+func (s *APIKeyStore) Update(id string, fn func(key *APIKey) error) (APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, ok := s.keys[id]
+	if !ok {
+		return APIKey{}, appErrors.APIKeyNotFoundError(id)
+	}
+
+	key := *previous
+	if err := fn(&key); err != nil {
+		return APIKey{}, err
+	}
+
+	if err := s.replace(previous, &key); err != nil {
+		return APIKey{}, err
+	}
+
+	return key, nil
+}
+
+// replace swaps previous, nil for a new key, for key and saves the store,
+// swapping previous back if the save fails. The caller holds s.mu.
+func (s *APIKeyStore) replace(previous, key *APIKey) error {
+	s.swap(previous, key)
+	if err := s.save(); err != nil {
+		s.swap(key, previous)
+		return err
+	}
+
+	return nil
+}
+
+// swap indexes key in place of previous. Either may be nil.
+func (s *APIKeyStore) swap(previous, key *APIKey) {
+	if previous != nil {
+		delete(s.byHash, previous.Hash)
+		delete(s.keys, previous.ID)
+	}
+	if key != nil {
+		s.keys[key.ID] = key
+		s.byHash[key.Hash] = key.ID
+	}
+}
+
+// Use finds the key with hash and applies fn to it. The key is updated
+// with the usage fn records, to be saved by the next Flush.
+func (s *APIKeyStore) Use(hash string, fn func(key *APIKey) error) (APIKey, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, ok := s.byHash[hash]
+	if !ok {
+		return APIKey{}, false, nil
+	}
+
+	key := s.keys[id]
+	usage := key.Usage
+	if err := fn(key); err != nil {
+		return *key, true, err
+	}
+
+	s.dirty = s.dirty || key.Usage != usage
+	return *key, true, nil
+}
+
+// Flush saves the usage recorded since the last save.
+func (s *APIKeyStore) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.dirty {
+		return nil
+	}
+
+	return s.save()
+}
+
+// save writes the keys to path with writeFileAtomic. The caller holds s.mu.
+func (s *APIKeyStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	keys := make([]*APIKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+
+	data, err := json.MarshalIndent(keys, "", "  ")
+	if err != nil {
+		return appErrors.APIKeyStoreError(err)
+	}
+
+	if err := writeFileAtomic(s.path, data); err != nil {
+		return appErrors.APIKeyStoreError(err)
+	}
+
+	s.dirty = false
+	return nil
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+)
+
+func TestAPIKeyStorePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+
+	store, err := LoadAPIKeyStore(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	key := APIKey{ID: "k1", Name: "billing", Hash: hashAPIKey("erk_one"), Scopes: []Scope{ScopeConvert}, DailyQuota: 10}
+	if err := store.Put(key); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	now := time.Date(2025, 11, 28, 10, 0, 0, 0, time.UTC)
+	if _, found, err := store.Use(key.Hash, func(key *APIKey) error {
+		key.Usage = KeyUsage{Day: "2025-11-28", Today: 1, Total: 1, LastUsed: now}
+		return nil
+	}); !found || err != nil {
+		t.Fatalf("Use() = %v, %v", found, err)
+	}
+	if err := store.Flush(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	reloaded, err := LoadAPIKeyStore(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	got, err := reloaded.Get("k1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got.Name != key.Name || got.UsedOn(now) != 1 || got.Usage.Total != 1 || !got.HasScope(ScopeConvert) || got.HasScope(ScopeRates) {
+		t.Errorf("Reloaded key %+v, expected %+v with one request", got, key)
+	}
+
+	// Replacing the key's hash, as a rotation does, retires the old one.
+	got.Hash = hashAPIKey("erk_two")
+	if err := reloaded.Put(got); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, found, _ := reloaded.Use(hashAPIKey("erk_one"), func(*APIKey) error { return nil }); found {
+		t.Error("Expected the previous hash to be forgotten")
+	}
+
+	var customErr *appErrors.CustomError
+	if _, err := reloaded.Get("missing"); !errors.As(err, &customErr) || customErr.Code != appErrors.ErrAPIKeyNotFound {
+		t.Errorf("Get(missing) error = %v, expected %s", err, appErrors.ErrAPIKeyNotFound)
+	}
+}
+
+func TestAPIKeyStoreUpdateRollsBackFailedSave(t *testing.T) {
+	dir := t.TempDir()
+	store, err := LoadAPIKeyStore(filepath.Join(dir, "keys.json"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	key := APIKey{ID: "k1", Hash: hashAPIKey("erk_one"), Scopes: []Scope{ScopeRates}}
+	if err := store.Put(key); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Usage recorded after a caller read the key survives the update.
+	store.Use(key.Hash, func(key *APIKey) error {
+		key.Usage.Total = 5
+		return nil
+	})
+	updated, err := store.Update("k1", func(key *APIKey) error {
+		key.Name = "renamed"
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if updated.Name != "renamed" || updated.Usage.Total != 5 {
+		t.Errorf("Update() = %+v, expected the new name and the recorded usage", updated)
+	}
+
+	// A directory where the file should be makes every save fail.
+	store.path = dir
+	if _, err := store.Update("k1", func(key *APIKey) error {
+		key.Hash = hashAPIKey("erk_two")
+		return nil
+	}); err == nil {
+		t.Fatal("Expected the save to fail")
+	}
+	if _, found, _ := store.Use(hashAPIKey("erk_one"), func(*APIKey) error { return nil }); !found {
+		t.Error("Expected the previous hash to be kept after a failed save")
+	}
+	if _, found, _ := store.Use(hashAPIKey("erk_two"), func(*APIKey) error { return nil }); found {
+		t.Error("Expected the new hash to be dropped after a failed save")
+	}
+
+	if err := store.Put(APIKey{ID: "k2", Hash: hashAPIKey("erk_three")}); err == nil {
+		t.Fatal("Expected the save to fail")
+	}
+	if _, err := store.Get("k2"); err == nil {
+		t.Error("Expected a key whose save failed not to be added")
+	}
+}
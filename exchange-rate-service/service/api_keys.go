@@ -0,0 +1,277 @@
+package service
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+)
+
+const (
+	defaultAPIKeysFile    = "api_keys.json"
+	apiKeyPrefix          = "erk_"
+	apiKeyUsageFlushEvery = time.Minute
+
+	// adminKeyID identifies the key configured with API_ADMIN_KEY.
+	adminKeyID = "admin"
+)
+
+// APIKeySpec is the user-supplied part of an API key, before validation.
+// ExpiresAt is an RFC 3339 time, or empty for a key that never expires.
+type APIKeySpec struct {
+	Name       string
+	Scopes     []string
+	DailyQuota int
+	ExpiresAt  string
+}
+
+// APIKeyService issues API keys, checks them on each request and counts
+// their usage against the daily quota.
+type APIKeyService struct {
+	store    *APIKeyStore
+	adminKey string
+	now      func() time.Time
+}
+
+func NewAPIKeyService() *APIKeyService {
+	keysFile := defaultAPIKeysFile
+	if value, ok := os.LookupEnv("API_KEYS_FILE"); ok {
+		keysFile = value
+	}
+
+	store, err := LoadAPIKeyStore(keysFile)
+	if err != nil {
+		panic(fmt.Sprintf("failed to load API keys: %v", err))
+	}
+
+	adminKey := os.Getenv("API_ADMIN_KEY")
+	if adminKey == "" && len(store.List()) == 0 {
+		log.Println("No API keys are configured; set API_ADMIN_KEY to issue them")
+	}
+
+	return newAPIKeyService(store, adminKey)
+}
+
+func newAPIKeyService(store *APIKeyStore, adminKey string) *APIKeyService {
+	return &APIKeyService{
+		store:    store,
+		adminKey: adminKey,
+		now:      time.Now,
+	}
+}
+
+// Start saves recorded usage every minute.
+func (s *APIKeyService) Start() {
+	go func() {
+		ticker := time.NewTicker(apiKeyUsageFlushEvery)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := s.store.Flush(); err != nil {
+				log.Printf("Failed to save API key usage: %v", err)
+			}
+		}
+	}()
+}
+
+// Authenticate returns the key for secret if it is active, grants scope
+// and has quota left today. The API_ADMIN_KEY is accepted for every scope
+// without a quota. The request is not counted; ChargeUsage does that once
+// the request has been accepted.
+func (s *APIKeyService) Authenticate(secret string, scope Scope) (APIKey, error) {
+	if s.adminKey != "" && subtle.ConstantTimeCompare([]byte(secret), []byte(s.adminKey)) == 1 {
+		return APIKey{ID: adminKeyID, Name: "API_ADMIN_KEY", Scopes: []Scope{ScopeAdmin}}, nil
+	}
+
+	now := s.now().UTC()
+	key, found, err := s.store.Use(hashAPIKey(secret), func(key *APIKey) error {
+		switch {
+		case !key.RevokedAt.IsZero():
+			return appErrors.APIKeyRevokedError()
+		case !key.ExpiresAt.IsZero() && !now.Before(key.ExpiresAt):
+			return appErrors.APIKeyExpiredError()
+		case !key.HasScope(scope):
+			return appErrors.InsufficientScopeError(string(scope))
+		}
+
+		return checkQuota(key, now)
+	})
+	if !found {
+		return APIKey{}, appErrors.UnauthorizedError()
+	}
+
+	return key, err
+}
+
+// ChargeUsage counts a request made with key, an authenticated key, against
+// its daily quota. It fails if the quota was used up since the key was
+// authenticated, or the key was rotated away.
+func (s *APIKeyService) ChargeUsage(key APIKey) (APIKey, error) {
+	if key.ID == adminKeyID {
+		return key, nil
+	}
+
+	now := s.now().UTC()
+	key, found, err := s.store.Use(key.Hash, func(key *APIKey) error {
+		if err := checkQuota(key, now); err != nil {
+			return err
+		}
+
+		key.Usage = KeyUsage{
+			Day:      now.Format("2006-01-02"),
+			Today:    key.UsedOn(now) + 1,
+			Total:    key.Usage.Total + 1,
+			LastUsed: now,
+		}
+		return nil
+	})
+	if !found {
+		return APIKey{}, appErrors.UnauthorizedError()
+	}
+
+	return key, err
+}
+
+// checkQuota fails when key has used up its daily quota on the day of now.
+func checkQuota(key *APIKey, now time.Time) error {
+	if key.DailyQuota > 0 && key.UsedOn(now) >= key.DailyQuota {
+		return appErrors.QuotaExceededError(fmt.Sprintf("daily limit of %d requests", key.DailyQuota))
+	}
+
+	return nil
+}
+
+// QuotaResetsAt returns when daily quotas next start afresh: midnight UTC
+// after now.
+func QuotaResetsAt(now time.Time) time.Time {
+	return now.UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+}
+
+func (s *APIKeyService) Keys() []APIKey {
+	return s.store.List()
+}
+
+func (s *APIKeyService) Key(id string) (APIKey, error) {
+	return s.store.Get(id)
+}
+
+// IssueKey creates a key from spec. The secret is returned only here.
+func (s *APIKeyService) IssueKey(spec APIKeySpec) (APIKey, string, error) {
+	now := s.now().UTC()
+	key := APIKey{
+		ID:        randomHex(8),
+		CreatedAt: now,
+	}
+
+	if err := s.apply(&key, spec, now); err != nil {
+		return APIKey{}, "", err
+	}
+
+	secret := s.newSecret(&key)
+	if err := s.store.Put(key); err != nil {
+		return APIKey{}, "", err
+	}
+
+	return key, secret, nil
+}
+
+// RotateKey replaces the secret of key id, keeping its settings and usage.
+// The previous secret stops working at once.
+func (s *APIKeyService) RotateKey(id string) (APIKey, string, error) {
+	var secret string
+	key, err := s.store.Update(id, func(key *APIKey) error {
+		if !key.RevokedAt.IsZero() {
+			return appErrors.InvalidAPIKeySpecError("a revoked key cannot be rotated")
+		}
+
+		key.RotatedAt = s.now().UTC()
+		secret = s.newSecret(key)
+		return nil
+	})
+	if err != nil {
+		return APIKey{}, "", err
+	}
+
+	return key, secret, nil
+}
+
+// RevokeKey disables key id. The key is kept, with its usage, for the
+// record.
+func (s *APIKeyService) RevokeKey(id string) error {
+	_, err := s.store.Update(id, func(key *APIKey) error {
+		if key.RevokedAt.IsZero() {
+			key.RevokedAt = s.now().UTC()
+		}
+		return nil
+	})
+
+	return err
+}
+
+// apply validates spec and copies it onto key.
+func (s *APIKeyService) apply(key *APIKey, spec APIKeySpec, now time.Time) error {
+	if len(spec.Scopes) == 0 {
+		return appErrors.InvalidAPIKeySpecError("at least one scope is required")
+	}
+
+	scopes := make([]Scope, 0, len(spec.Scopes))
+	for _, name := range spec.Scopes {
+		scope, ok := parseScope(name)
+		if !ok {
+			return appErrors.InvalidAPIKeySpecError("unknown scope " + name)
+		}
+		scopes = append(scopes, scope)
+	}
+
+	if spec.DailyQuota < 0 {
+		return appErrors.InvalidAPIKeySpecError("daily_quota must not be negative")
+	}
+
+	var expiresAt time.Time
+	if spec.ExpiresAt != "" {
+		var err error
+		expiresAt, err = time.Parse(time.RFC3339, spec.ExpiresAt)
+		if err != nil {
+			return appErrors.InvalidAPIKeySpecError("expires_at must be an RFC 3339 time")
+		}
+		if !expiresAt.After(now) {
+			return appErrors.InvalidAPIKeySpecError("expires_at must be in the future")
+		}
+	}
+
+	key.Name = spec.Name
+	key.Scopes = scopes
+	key.DailyQuota = spec.DailyQuota
+	key.ExpiresAt = expiresAt.UTC()
+
+	return nil
+}
+
+// newSecret generates a secret for key and stores its hash and prefix.
+func (s *APIKeyService) newSecret(key *APIKey) string {
+	secret := apiKeyPrefix + randomHex(24)
+	key.Hash = hashAPIKey(secret)
+	key.Prefix = secret[:len(apiKeyPrefix)+6]
+
+	return secret
+}
+
+func parseScope(name string) (Scope, bool) {
+	for _, scope := range Scopes {
+		if string(scope) == name {
+			return scope, true
+		}
+	}
+
+	return "", false
+}
+
+func hashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
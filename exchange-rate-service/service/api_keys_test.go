@@ -0,0 +1,137 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+)
+
+func newTestAPIKeyService(t *testing.T, now *time.Time) *APIKeyService {
+	store, err := LoadAPIKeyStore("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	s := newAPIKeyService(store, "bootstrap")
+	s.now = func() time.Time { return *now }
+
+	return s
+}
+
+func expectCode(t *testing.T, err error, code appErrors.ErrorCode) {
+	t.Helper()
+
+	var customErr *appErrors.CustomError
+	if !errors.As(err, &customErr) || customErr.Code != code {
+		t.Errorf("Error %v, expected %s", err, code)
+	}
+}
+
+func TestAuthenticateChecksScopeAndQuota(t *testing.T) {
+	now := time.Date(2025, 11, 28, 23, 0, 0, 0, time.UTC)
+	s := newTestAPIKeyService(t, &now)
+
+	key, secret, err := s.IssueKey(APIKeySpec{Name: "app", Scopes: []string{"convert"}, DailyQuota: 2})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if key.Hash == secret || key.Hash != hashAPIKey(secret) {
+		t.Errorf("Expected only the hash of the secret to be kept")
+	}
+
+	if _, err := s.Authenticate(secret, ScopeRates); err == nil {
+		t.Error("Expected the rates scope to be refused")
+	} else {
+		expectCode(t, err, appErrors.ErrInsufficientScope)
+	}
+
+	// Authenticating alone does not count against the quota.
+	for i := 0; i < 3; i++ {
+		if _, err := s.Authenticate(secret, ScopeConvert); err != nil {
+			t.Fatalf("Authentication %d: unexpected error: %v", i, err)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := s.ChargeUsage(key); err != nil {
+			t.Fatalf("Request %d: unexpected error: %v", i, err)
+		}
+	}
+	_, err = s.Authenticate(secret, ScopeConvert)
+	expectCode(t, err, appErrors.ErrQuotaExceeded)
+	_, err = s.ChargeUsage(key)
+	expectCode(t, err, appErrors.ErrQuotaExceeded)
+
+	// The quota starts afresh on the next UTC day.
+	now = now.Add(2 * time.Hour)
+	if _, err := s.Authenticate(secret, ScopeConvert); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	used, err := s.ChargeUsage(key)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if used.UsedOn(now) != 1 || used.Usage.Total != 3 {
+		t.Errorf("Usage %+v, expected 1 today and 3 in total", used.Usage)
+	}
+
+	_, err = s.Authenticate("erk_unknown", ScopeConvert)
+	expectCode(t, err, appErrors.ErrUnauthorized)
+
+	if _, err := s.Authenticate("bootstrap", ScopeAdmin); err != nil {
+		t.Errorf("Expected API_ADMIN_KEY to be accepted, got %v", err)
+	}
+}
+
+func TestRotateRevokeAndExpire(t *testing.T) {
+	now := time.Date(2025, 11, 28, 10, 0, 0, 0, time.UTC)
+	s := newTestAPIKeyService(t, &now)
+
+	key, secret, err := s.IssueKey(APIKeySpec{Name: "app", Scopes: []string{"rates"}, ExpiresAt: "2025-12-01T00:00:00Z"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	_, rotated, err := s.RotateKey(key.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	_, err = s.Authenticate(secret, ScopeRates)
+	expectCode(t, err, appErrors.ErrUnauthorized)
+	if _, err := s.Authenticate(rotated, ScopeRates); err != nil {
+		t.Errorf("Expected the rotated secret to work, got %v", err)
+	}
+
+	now = time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	_, err = s.Authenticate(rotated, ScopeRates)
+	expectCode(t, err, appErrors.ErrAPIKeyExpired)
+
+	if err := s.RevokeKey(key.ID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	_, err = s.Authenticate(rotated, ScopeRates)
+	expectCode(t, err, appErrors.ErrAPIKeyRevoked)
+
+	_, _, err = s.RotateKey(key.ID)
+	expectCode(t, err, appErrors.ErrInvalidAPIKeySpec)
+}
+
+func TestIssueKeyValidates(t *testing.T) {
+	now := time.Date(2025, 11, 28, 10, 0, 0, 0, time.UTC)
+	s := newTestAPIKeyService(t, &now)
+
+	specs := []APIKeySpec{
+		{Name: "no scopes"},
+		{Name: "unknown scope", Scopes: []string{"write"}},
+		{Name: "negative quota", Scopes: []string{"rates"}, DailyQuota: -1},
+		{Name: "bad expiry", Scopes: []string{"rates"}, ExpiresAt: "tomorrow"},
+		{Name: "past expiry", Scopes: []string{"rates"}, ExpiresAt: "2025-11-01T00:00:00Z"},
+	}
+
+	for _, spec := range specs {
+		_, _, err := s.IssueKey(spec)
+		expectCode(t, err, appErrors.ErrInvalidAPIKeySpec)
+	}
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"time"
+
+	"github.com/yourusername/exchange-rate-service/money"
+)
+
+// MaxBatchSize is the largest number of conversions accepted in one batch.
+const MaxBatchSize = 10000
+
+// ConversionRequest is one item of a batch conversion. Date selects
+// historical rates and is nil for the latest ones. Rounding, when set,
+// replaces the batch rounding for the item. Reverse requests find the
+// amount of To needed to receive Amount, like ConvertCurrencyReverse, and
+// are not rounded.
+type ConversionRequest struct {
+	Amount   money.Money
+	To       string
+	Date     *time.Time
+	Rounding money.Rounding
+	Reverse  bool
+}
+
+// BatchResult holds either the conversion or the error for one item.
+type BatchResult struct {
+	Conversion *Conversion
+	Err        error
+}
+
+type snapshotResult struct {
+	snapshot *RateSnapshot
+	err      error
+}
+
+// ConvertBatch converts every request independently, so a failing item does
+// not affect the others. Items on the same date share one rate lookup, and
+// distinct dates are loaded with at most historicalConcurrency upstream
+// calls in flight. Results are returned in the order of requests.
+func (s *RateFetcherService) ConvertBatch(requests []ConversionRequest, rounding money.Rounding) []BatchResult {
+	results := make([]BatchResult, len(requests))
+
+	normalized := make([]ConversionRequest, len(requests))
+	itemDates := make([]int, len(requests))
+
+	var dates []*time.Time
+	dateIndex := make(map[string]int)
+
+	for i, request := range requests {
+		amount, to := s.normalizeMoney(request.Amount), s.currencies.Normalize(request.To)
+		from, target := amount.Currency(), to
+		if request.Reverse {
+			from, target = to, amount.Currency()
+		}
+		if err := s.validate(from, target, amount, request.Date); err != nil {
+			results[i].Err = err
+			itemDates[i] = -1
+			continue
+		}
+		normalized[i] = request
+		normalized[i].Amount, normalized[i].To = amount, to
+
+		key := "latest"
+		if request.Date != nil {
+			key = request.Date.Format("2006-01-02")
+		}
+
+		index, ok := dateIndex[key]
+		if !ok {
+			index = len(dates)
+			dateIndex[key] = index
+			dates = append(dates, request.Date)
+		}
+		itemDates[i] = index
+	}
+
+	snapshots := make([]snapshotResult, len(dates))
+	s.forEachBounded(len(dates), func(i int) {
+		snapshots[i].snapshot, snapshots[i].err = s.getRates(dates[i])
+	})
+
+	for i, request := range normalized {
+		if itemDates[i] < 0 {
+			continue
+		}
+
+		snapshot := snapshots[itemDates[i]]
+		if snapshot.err != nil {
+			results[i].Err = snapshot.err
+			continue
+		}
+
+		if request.Reverse {
+			source, err := s.converter.ConvertReverse(request.To, request.Amount, snapshot.snapshot.Rates)
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+
+			results[i].Conversion, results[i].Err = s.newConversion(source, request.Amount, snapshot.snapshot)
+			continue
+		}
+
+		itemRounding := rounding
+		if !request.Rounding.IsZero() {
+			itemRounding = request.Rounding
+		}
+
+		result, err := s.converter.ConvertWithRounding(request.Amount, request.To, snapshot.snapshot.Rates, itemRounding)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		results[i].Conversion, results[i].Err = s.newConversion(request.Amount, result, snapshot.snapshot)
+	}
+
+	return results
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+	"github.com/yourusername/exchange-rate-service/money"
+)
+
+func TestConvertBatch(t *testing.T) {
+	s := newTestRateFetcher(t, "USD,EUR,INR")
+	s.cache.SetLatestRates(map[string]decimal.Decimal{
+		"USD": decimal.NewFromInt(1),
+		"EUR": decimal.RequireFromString("0.8"),
+		"INR": decimal.NewFromInt(80),
+	})
+
+	date := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -7)
+	s.cache.SetHistoricalRates(date, map[string]decimal.Decimal{
+		"USD": decimal.NewFromInt(1),
+		"EUR": decimal.RequireFromString("0.5"),
+		"INR": decimal.NewFromInt(85),
+	})
+	tooOld := date.AddDate(0, 0, -100)
+
+	requests := []ConversionRequest{
+		{Amount: money.New(decimal.NewFromInt(100), "USD"), To: "eur"},
+		{Amount: money.New(decimal.NewFromInt(100), "USD"), To: "EUR", Date: &date},
+		{Amount: money.New(decimal.NewFromInt(100), "USD"), To: "XYZ"},
+		{Amount: money.New(decimal.NewFromInt(100), "USD"), To: "INR", Date: &tooOld},
+	}
+
+	results := s.ConvertBatch(requests, money.Rounding{})
+	if len(results) != len(requests) {
+		t.Fatalf("Expected %d results, got %d", len(requests), len(results))
+	}
+
+	expected := []string{"80.00", "50.00"}
+	for i, want := range expected {
+		if results[i].Err != nil {
+			t.Fatalf("Item %d: unexpected error: %v", i, results[i].Err)
+		}
+		if got := results[i].Conversion.Result.AmountString(); got != want {
+			t.Errorf("Item %d = %s, expected %s", i, got, want)
+		}
+	}
+
+	expectedCodes := map[int]appErrors.ErrorCode{
+		2: appErrors.ErrUnsupportedCurrency,
+		3: appErrors.ErrDateTooOld,
+	}
+	for i, code := range expectedCodes {
+		var customErr *appErrors.CustomError
+		if !errors.As(results[i].Err, &customErr) || customErr.Code != code {
+			t.Errorf("Item %d error = %v, expected %s", i, results[i].Err, code)
+		}
+	}
+}
+
+func TestConvertBatchItemOptions(t *testing.T) {
+	s := newTestRateFetcher(t, "USD,EUR")
+	s.cache.SetLatestRates(map[string]decimal.Decimal{
+		"USD": decimal.NewFromInt(1),
+		"EUR": decimal.RequireFromString("0.8"),
+	})
+
+	places := int32(0)
+	requests := []ConversionRequest{
+		{Amount: money.New(decimal.RequireFromString("10.55"), "USD"), To: "EUR"},
+		{Amount: money.New(decimal.RequireFromString("10.55"), "USD"), To: "EUR", Rounding: money.Rounding{Mode: money.RoundDown, Places: &places}},
+		{Amount: money.New(decimal.NewFromInt(80), "EUR"), To: "USD", Reverse: true},
+		{Amount: money.New(decimal.NewFromInt(80), "EUR"), To: "XYZ", Reverse: true},
+	}
+
+	results := s.ConvertBatch(requests, money.Rounding{Mode: money.RoundHalfUp})
+	for i, want := range []string{"8.44", "8"} {
+		if results[i].Err != nil {
+			t.Fatalf("Item %d: unexpected error: %v", i, results[i].Err)
+		}
+		if got := results[i].Conversion.Result.Amount().String(); got != want {
+			t.Errorf("Item %d = %s, expected %s", i, got, want)
+		}
+	}
+
+	if results[2].Err != nil {
+		t.Fatalf("Reverse item: unexpected error: %v", results[2].Err)
+	}
+	if got := results[2].Conversion.Amount.Amount().String(); got != "100" {
+		t.Errorf("Reverse item source = %s USD, expected 100", got)
+	}
+
+	var customErr *appErrors.CustomError
+	if !errors.As(results[3].Err, &customErr) || customErr.Code != appErrors.ErrUnsupportedCurrency {
+		t.Errorf("Reverse item error = %v, expected %s", results[3].Err, appErrors.ErrUnsupportedCurrency)
+	}
+}
@@ -10,16 +10,19 @@ import (
 type Cache struct {
 	latestRates map[string]decimal.Decimal
 	lastUpdated time.Time
+	version     uint64
 
-	historicalRates map[string]map[string]decimal.Decimal
+	historicalRates   map[string]map[string]decimal.Decimal
+	historicalFetched map[string]time.Time
 
 	mu sync.RWMutex
 }
 
 func NewCache() *Cache {
 	return &Cache{
-		latestRates:     make(map[string]decimal.Decimal),
-		historicalRates: make(map[string]map[string]decimal.Decimal),
+		latestRates:       make(map[string]decimal.Decimal),
+		historicalRates:   make(map[string]map[string]decimal.Decimal),
+		historicalFetched: make(map[string]time.Time),
 	}
 }
 
@@ -40,12 +43,36 @@ func (c *Cache) GetLatestRates() (map[string]decimal.Decimal, bool) {
 
 }
 
-func (c *Cache) SetLatestRates(rates map[string]decimal.Decimal) {
+// SetLatestRates stores rates as a new version of the latest rates and
+// returns that version and when it was stored.
+func (c *Cache) SetLatestRates(rates map[string]decimal.Decimal) (uint64, time.Time) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	c.latestRates = rates
 	c.lastUpdated = time.Now()
+	c.version++
+
+	return c.version, c.lastUpdated
+}
+
+// GetLatestVersion returns the latest rates along with their version, which
+// counts how many times the latest rates have been set, and when they were
+// stored. All three are read together, so they describe the same rates.
+func (c *Cache) GetLatestVersion() (map[string]decimal.Decimal, uint64, time.Time, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.latestRates) == 0 {
+		return nil, 0, time.Time{}, false
+	}
+
+	rates := make(map[string]decimal.Decimal, len(c.latestRates))
+	for code, rate := range c.latestRates {
+		rates[code] = rate
+	}
+
+	return rates, c.version, c.lastUpdated, true
 }
 
 func (c *Cache) GetLastUpdated() time.Time {
@@ -74,13 +101,42 @@ func (c *Cache) GetHistoricalRates(date time.Time) (map[string]decimal.Decimal,
 
 }
 
+// HasHistoricalRates reports whether the rates for date are cached, without
+// copying them.
+func (c *Cache) HasHistoricalRates(date time.Time) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, exists := c.historicalRates[date.Format("2006-01-02")]
+	return exists
+}
+
 func (c *Cache) SetHistoricalRates(date time.Time, rates map[string]decimal.Decimal) {
 	dateKey := date.Format("2006-01-02")
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	c.historicalRates[dateKey] = rates
+	c.historicalFetched[dateKey] = time.Now()
+
+}
+
+// GetHistoricalFetchedAt returns when the rates for date were stored, or the
+// zero time if they are not cached.
+func (c *Cache) GetHistoricalFetchedAt(date time.Time) time.Time {
+	dateKey := date.Format("2006-01-02")
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.historicalFetched[dateKey]
+}
+
+// HistoricalCount returns the number of dates with cached rates.
+func (c *Cache) HistoricalCount() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 
+	return len(c.historicalRates)
 }
 
 func (c *Cache) ClearOldHistoricalData() {
@@ -97,6 +153,7 @@ func (c *Cache) ClearOldHistoricalData() {
 
 		if date.Before(cutoffDate) {
 			delete(c.historicalRates, dateStr)
+			delete(c.historicalFetched, dateStr)
 		}
 	}
 }
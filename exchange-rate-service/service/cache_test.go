@@ -96,6 +96,27 @@ func TestGetLastUpdated(t *testing.T) {
 	}
 }
 
+func TestSetLatestRatesReturnsStoredVersion(t *testing.T) {
+	cache := NewCache()
+
+	for expected := uint64(1); expected <= 2; expected++ {
+		version, updatedAt := cache.SetLatestRates(map[string]decimal.Decimal{
+			"USD": decimal.NewFromInt(1),
+		})
+
+		_, storedVersion, storedAt, found := cache.GetLatestVersion()
+		if !found {
+			t.Fatal("Expected to find rates in cache")
+		}
+		if version != expected || storedVersion != expected {
+			t.Errorf("Version %d, stored %d, expected %d", version, storedVersion, expected)
+		}
+		if !updatedAt.Equal(storedAt) {
+			t.Errorf("Updated at %s, stored at %s", updatedAt, storedAt)
+		}
+	}
+}
+
 func TestClearHistoricalValues(t *testing.T) {
 	cache := NewCache()
 	oldDate := time.Now().AddDate(0, 0, -100)
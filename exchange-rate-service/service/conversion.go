@@ -0,0 +1,37 @@
+package service
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/exchange-rate-service/money"
+)
+
+// RateSnapshot is a USD-based rate table together with where and when it
+// came from. Date is set for historical tables only.
+type RateSnapshot struct {
+	Rates     map[string]decimal.Decimal
+	Date      *time.Time
+	UpdatedAt time.Time
+	Provider  string
+	Stale     bool
+
+	// Version identifies the refresh the latest rates came from. It is zero
+	// for historical snapshots.
+	Version uint64
+}
+
+// Age is how long ago the snapshot was fetched from the provider.
+func (s *RateSnapshot) Age() time.Duration {
+	return time.Since(s.UpdatedAt)
+}
+
+// Conversion is the outcome of converting Amount into Result, with the rate
+// that was applied and the provenance of that rate.
+type Conversion struct {
+	Amount      money.Money
+	Result      money.Money
+	Rate        decimal.Decimal
+	InverseRate decimal.Decimal
+	Snapshot    *RateSnapshot
+}
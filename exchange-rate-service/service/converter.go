@@ -3,6 +3,7 @@ package service
 import (
 	"github.com/shopspring/decimal"
 	appErrors "github.com/yourusername/exchange-rate-service/errors"
+	"github.com/yourusername/exchange-rate-service/money"
 )
 
 type Converter struct {
@@ -12,30 +13,84 @@ func NewConverter() *Converter {
 	return &Converter{}
 }
 
-func (c *Converter) Convert(from, to string, amount decimal.Decimal, rates map[string]decimal.Decimal) (string, error) {
+// Convert converts amount into to at full precision.
+func (c *Converter) Convert(amount money.Money, to string, rates map[string]decimal.Decimal) (money.Money, error) {
+	return c.ConvertWithRounding(amount, to, rates, money.Rounding{})
+}
+
+// ConvertWithRounding converts amount into to and rounds the result with
+// rounding. A zero rounding leaves the result unrounded; a mode without
+// places rounds to the currency's minor units.
+func (c *Converter) ConvertWithRounding(amount money.Money, to string, rates map[string]decimal.Decimal, rounding money.Rounding) (money.Money, error) {
+	from := amount.Currency()
+	fromRate, toRate, err := c.lookupRates(from, to, rates)
+	if err != nil {
+		return money.Money{}, err
+	}
+
+	result := amount
+	if from != to {
+		result = money.New(amount.Amount().Mul(toRate).Div(fromRate), to)
+	}
+
+	if rounding.IsZero() {
+		return result, nil
+	}
+
+	return result.RoundWith(rounding), nil
+}
+
+// ConvertReverse returns the smallest amount of from, rounded up to its
+// minor units, that converts to at least target.
+func (c *Converter) ConvertReverse(from string, target money.Money, rates map[string]decimal.Decimal) (money.Money, error) {
+	to := target.Currency()
+	fromRate, toRate, err := c.lookupRates(from, to, rates)
+	if err != nil {
+		return money.Money{}, err
+	}
+
+	if from == to {
+		return target.RoundUp(), nil
+	}
+
+	result := money.New(target.Amount().Mul(fromRate).Div(toRate), from)
+
+	return result.RoundUp(), nil
+}
+
+// Rate returns the mid-market rate for one unit of from expressed in to.
+func (c *Converter) Rate(from, to string, rates map[string]decimal.Decimal) (decimal.Decimal, error) {
+	fromRate, toRate, err := c.lookupRates(from, to, rates)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	if from == to {
+		return decimal.NewFromInt(1), nil
+	}
+
+	return toRate.Div(fromRate), nil
+}
+
+func (c *Converter) lookupRates(from, to string, rates map[string]decimal.Decimal) (decimal.Decimal, decimal.Decimal, error) {
 	fromRate, fromExists := rates[from]
 	toRate, toExists := rates[to]
 
 	if !fromExists {
-		return "", appErrors.MissingRateError(from)
+		return decimal.Zero, decimal.Zero, appErrors.MissingRateError(from)
 	}
 
 	if !toExists {
-		return "", appErrors.MissingRateError(to)
+		return decimal.Zero, decimal.Zero, appErrors.MissingRateError(to)
 	}
 
-	if fromRate == decimal.NewFromInt(0) {
-		return "", appErrors.InvalidRateError(from)
+	if fromRate.IsZero() {
+		return decimal.Zero, decimal.Zero, appErrors.InvalidRateError(from)
 	}
 
-	if toRate == decimal.NewFromInt(0) {
-		return "", appErrors.InvalidRateError(to)
+	if toRate.IsZero() {
+		return decimal.Zero, decimal.Zero, appErrors.InvalidRateError(to)
 	}
-	if from == to {
-		return amount.String(), nil
-	}
-
-	result := amount.Mul(toRate).Div(fromRate)
 
-	return result.String(), nil
+	return fromRate, toRate, nil
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/shopspring/decimal"
+	"github.com/yourusername/exchange-rate-service/money"
 )
 
 func TestConvert(t *testing.T) {
@@ -64,21 +65,51 @@ func TestConvert(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			amount := decimal.RequireFromString(tt.amount)
-			result, err := converter.Convert(tt.from, tt.to, amount, rates)
+			amount := money.New(decimal.RequireFromString(tt.amount), tt.from)
+			result, err := converter.ConvertWithRounding(amount, tt.to, rates, money.Rounding{Mode: money.RoundHalfUp})
 
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
 				return
 			}
 
-			if result != tt.expected {
-				t.Errorf("Convert() = %v, expected %v", result, tt.expected)
+			if result.AmountString() != tt.expected {
+				t.Errorf("Convert() = %v, expected %v", result.AmountString(), tt.expected)
 			}
 		})
 	}
 }
 
+func TestConvertKeepsFullPrecision(t *testing.T) {
+	converter := NewConverter()
+
+	rates := map[string]decimal.Decimal{
+		"USD": decimal.NewFromInt(1),
+		"JPY": decimal.RequireFromString("149.5"),
+		"BTC": decimal.RequireFromString("0.0000153"),
+	}
+
+	tests := []struct {
+		amount   money.Money
+		to       string
+		expected string
+	}{
+		{money.New(decimal.RequireFromString("10.25"), "USD"), "JPY", "1532.375"},
+		{money.New(decimal.NewFromInt(1), "USD"), "BTC", "0.0000153"},
+		{money.New(decimal.NewFromInt(100), "USD"), "USD", "100"},
+	}
+
+	for _, tt := range tests {
+		result, err := converter.Convert(tt.amount, tt.to, rates)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Amount().String() != tt.expected {
+			t.Errorf("Convert(%s, %s) = %s, expected %s", tt.amount, tt.to, result.Amount(), tt.expected)
+		}
+	}
+}
+
 func TestConvertMissingRate(t *testing.T) {
 	converter := NewConverter()
 
@@ -87,8 +118,8 @@ func TestConvertMissingRate(t *testing.T) {
 		"INR": decimal.NewFromFloat(83.12),
 	}
 
-	amount := decimal.NewFromInt(100)
-	_, err := converter.Convert("USD", "EUR", amount, rates)
+	amount := money.New(decimal.NewFromInt(100), "USD")
+	_, err := converter.Convert(amount, "EUR", rates)
 
 	if err == nil {
 		t.Error("Expected error for missing rate")
@@ -103,10 +134,69 @@ func TestConvertZeroRate(t *testing.T) {
 		"EUR": decimal.Zero,
 	}
 
-	amount := decimal.NewFromInt(100)
-	_, err := converter.Convert("USD", "EUR", amount, rates)
+	amount := money.New(decimal.NewFromInt(100), "USD")
+	_, err := converter.Convert(amount, "EUR", rates)
 
 	if err == nil {
 		t.Error("Expected error for zero rate")
 	}
 }
+
+func TestConvertReverse(t *testing.T) {
+	converter := NewConverter()
+
+	rates := map[string]decimal.Decimal{
+		"USD": decimal.NewFromInt(1),
+		"INR": decimal.NewFromFloat(83.12),
+		"EUR": decimal.NewFromFloat(0.92),
+	}
+
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		target   string
+		expected string
+	}{
+		{
+			name:     "USD for INR",
+			from:     "USD",
+			to:       "INR",
+			target:   "8312",
+			expected: "100.00",
+		},
+		{
+			name:     "Rounds up",
+			from:     "USD",
+			to:       "INR",
+			target:   "1000",
+			expected: "12.04",
+		},
+		{
+			name:     "Same currency",
+			from:     "EUR",
+			to:       "EUR",
+			target:   "50",
+			expected: "50.00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := money.New(decimal.RequireFromString(tt.target), tt.to)
+			result, err := converter.ConvertReverse(tt.from, target, rates)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if result.AmountString() != tt.expected {
+				t.Errorf("ConvertReverse() = %v, expected %v", result.AmountString(), tt.expected)
+			}
+
+			forward, _ := converter.Convert(result, tt.to, rates)
+			if short, _ := forward.LessThan(target); short {
+				t.Errorf("Converting %s back gives %s, short of %s", result, forward, target)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CrossRateMatrix holds the rate of every supported currency against every
+// other, all derived from one snapshot so the cells are mutually consistent.
+// Rates[from][to] is the amount of to per unit of from.
+type CrossRateMatrix struct {
+	Currencies []string
+	Rates      map[string]map[string]decimal.Decimal
+	Snapshot   *RateSnapshot
+}
+
+// GetMatrix returns the cross-rate matrix for the latest rates, which is
+// precomputed on every refresh, or builds one for date when it is set.
+func (s *RateFetcherService) GetMatrix(date *time.Time) (*CrossRateMatrix, error) {
+	if err := validateDate(date); err != nil {
+		return nil, err
+	}
+
+	if date == nil {
+		matrix := s.latestMatrix.Load()
+		if matrix == nil {
+			snapshot, err := s.getRates(nil)
+			if err != nil {
+				return nil, err
+			}
+			return s.buildMatrix(snapshot), nil
+		}
+
+		snapshot := *matrix.Snapshot
+		snapshot.Stale = s.isStale(snapshot.UpdatedAt)
+
+		return &CrossRateMatrix{
+			Currencies: matrix.Currencies,
+			Rates:      matrix.Rates,
+			Snapshot:   &snapshot,
+		}, nil
+	}
+
+	snapshot, err := s.getRates(date)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildMatrix(snapshot), nil
+}
+
+// buildMatrix computes the matrix for the supported currencies present in
+// snapshot.
+func (s *RateFetcherService) buildMatrix(snapshot *RateSnapshot) *CrossRateMatrix {
+	var currencies []string
+	for _, currency := range s.currencies.List() {
+		if _, ok := snapshot.Rates[currency.Code]; ok {
+			currencies = append(currencies, currency.Code)
+		}
+	}
+
+	rates := make(map[string]map[string]decimal.Decimal, len(currencies))
+	for _, from := range currencies {
+		row := make(map[string]decimal.Decimal, len(currencies))
+		for _, to := range currencies {
+			rate, err := s.converter.Rate(from, to, snapshot.Rates)
+			if err != nil {
+				continue
+			}
+			row[to] = rate
+		}
+		rates[from] = row
+	}
+
+	return &CrossRateMatrix{
+		Currencies: currencies,
+		Rates:      rates,
+		Snapshot:   snapshot,
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestBuildMatrix(t *testing.T) {
+	s := newTestRateFetcher(t, "USD,EUR,INR,GBP")
+	snapshot := &RateSnapshot{
+		Rates: map[string]decimal.Decimal{
+			"USD": decimal.NewFromInt(1),
+			"EUR": decimal.RequireFromString("0.8"),
+			"INR": decimal.NewFromInt(80),
+		},
+	}
+
+	matrix := s.buildMatrix(snapshot)
+
+	if len(matrix.Currencies) != 3 {
+		t.Fatalf("Expected GBP to be skipped without a rate, got %v", matrix.Currencies)
+	}
+
+	for _, code := range matrix.Currencies {
+		if !matrix.Rates[code][code].Equal(decimal.NewFromInt(1)) {
+			t.Errorf("Diagonal %s/%s = %s, expected 1", code, code, matrix.Rates[code][code])
+		}
+	}
+
+	if !matrix.Rates["EUR"]["INR"].Equal(decimal.NewFromInt(100)) {
+		t.Errorf("EUR/INR = %s, expected 100", matrix.Rates["EUR"]["INR"])
+	}
+
+	product := matrix.Rates["EUR"]["INR"].Mul(matrix.Rates["INR"]["EUR"])
+	if !product.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("EUR/INR × INR/EUR = %s, expected 1", product)
+	}
+}
+
+func TestGetMatrixUsesPrecomputed(t *testing.T) {
+	s := newTestRateFetcher(t, "USD,EUR")
+	s.staleAfter = time.Hour
+
+	rates := map[string]decimal.Decimal{
+		"USD": decimal.NewFromInt(1),
+		"EUR": decimal.RequireFromString("0.8"),
+	}
+	version, updatedAt := s.cache.SetLatestRates(rates)
+	s.latestMatrix.Store(s.buildMatrix(s.latestSnapshot(rates, version, updatedAt)))
+
+	matrix, err := s.GetMatrix(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !matrix.Rates["USD"]["EUR"].Equal(decimal.RequireFromString("0.8")) {
+		t.Errorf("USD/EUR = %s, expected 0.8", matrix.Rates["USD"]["EUR"])
+	}
+	if matrix.Snapshot.Stale {
+		t.Error("Freshly loaded rates should not be stale")
+	}
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const defaultCurrencyAliases = "RMB=CNY"
+
+// Redenomination replaces a legacy currency with its successor at a fixed
+// official factor from Effective onwards. Factor is the number of legacy
+// units per successor unit.
+type Redenomination struct {
+	Legacy    Currency
+	Successor string
+	Factor    decimal.Decimal
+	Effective time.Time
+}
+
+var redenominations = map[string]Redenomination{
+	"HRK": {
+		Legacy:    Currency{Code: "HRK", Name: "Kuna", Numeric: "191", MinorUnits: 2, Symbol: "kn"},
+		Successor: "EUR",
+		Factor:    decimal.RequireFromString("7.53450"),
+		Effective: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+	},
+	"BGN": {
+		Legacy:    Currency{Code: "BGN", Name: "Bulgarian Lev", Numeric: "975", MinorUnits: 2, Symbol: "лв."},
+		Successor: "EUR",
+		Factor:    decimal.RequireFromString("1.95583"),
+		Effective: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	},
+}
+
+// LoadAliases parses a comma-separated list of ALIAS=CODE pairs, for
+// example "RMB=CNY". An empty spec yields the default aliases.
+func LoadAliases(spec string) (map[string]string, error) {
+	if spec == "" {
+		spec = defaultCurrencyAliases
+	}
+
+	aliases := make(map[string]string)
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		alias, code, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid currency alias %q, expected ALIAS=CODE", pair)
+		}
+
+		aliases[normalizeCode(alias)] = normalizeCode(code)
+	}
+
+	return aliases, nil
+}
+
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// applyRedenominations returns a copy of rates with every legacy currency
+// that had been replaced by date priced from its successor at the official
+// factor. Before the changeover the provider's own quote for the legacy code
+// is left untouched.
+func applyRedenominations(rates map[string]decimal.Decimal, date time.Time) map[string]decimal.Decimal {
+	adjusted := make(map[string]decimal.Decimal, len(rates))
+	for code, rate := range rates {
+		adjusted[code] = rate
+	}
+
+	for code, redenomination := range redenominations {
+		if date.Before(redenomination.Effective) {
+			continue
+		}
+
+		successorRate, ok := rates[redenomination.Successor]
+		if !ok {
+			continue
+		}
+
+		adjusted[code] = successorRate.Mul(redenomination.Factor)
+	}
+
+	return adjusted
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/exchange-rate-service/money"
+)
+
+func TestNormalizeAliases(t *testing.T) {
+	aliases, err := LoadAliases("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	currencies, _ := LoadCurrencies("USD,CNY", "")
+	registry := NewCurrencyRegistry(currencies, aliases)
+
+	tests := map[string]string{
+		"usd":   "USD",
+		" Usd ": "USD",
+		"rmb":   "CNY",
+		"CNY":   "CNY",
+	}
+
+	for input, expected := range tests {
+		if got := registry.Normalize(input); got != expected {
+			t.Errorf("Normalize(%q) = %s, expected %s", input, got, expected)
+		}
+	}
+
+	if _, err := LoadAliases("RMB"); err == nil {
+		t.Error("Expected error for alias without target")
+	}
+}
+
+func TestLegacyCurrencySupport(t *testing.T) {
+	currencies, _ := LoadCurrencies("USD,EUR", "")
+	registry := NewCurrencyRegistry(currencies, nil)
+
+	registry.SetQuoted(map[string]decimal.Decimal{
+		"USD": decimal.NewFromInt(1),
+		"EUR": decimal.NewFromFloat(0.92),
+	})
+
+	if !registry.IsSupported("HRK") {
+		t.Error("HRK should be supported while EUR is")
+	}
+}
+
+func TestApplyRedenominations(t *testing.T) {
+	converter := NewConverter()
+	legacyRate := decimal.RequireFromString("6.9")
+	rates := map[string]decimal.Decimal{
+		"USD": decimal.NewFromInt(1),
+		"EUR": decimal.RequireFromString("0.92"),
+		"HRK": legacyRate,
+	}
+
+	after := applyRedenominations(rates, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	result, err := converter.Convert(money.New(decimal.RequireFromString("753.45"), "HRK"), "EUR", after)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.String() != "100.00 EUR" {
+		t.Errorf("753.45 HRK after changeover = %s, expected 100.00 EUR", result)
+	}
+
+	before := applyRedenominations(rates, time.Date(2022, 12, 31, 0, 0, 0, 0, time.UTC))
+	if !before["HRK"].Equal(legacyRate) {
+		t.Errorf("Expected provider HRK rate %s before changeover, got %s", legacyRate, before["HRK"])
+	}
+
+	if !rates["HRK"].Equal(legacyRate) {
+		t.Error("applyRedenominations should not modify its input")
+	}
+}
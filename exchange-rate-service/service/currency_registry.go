@@ -0,0 +1,209 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/exchange-rate-service/money"
+)
+
+const defaultSupportedCurrencies = "USD,INR,EUR,JPY,GBP,BTC"
+
+// Currency carries the ISO 4217 metadata for a supported currency. Numeric
+// is empty for codes outside ISO 4217, such as BTC.
+type Currency struct {
+	Code       string `json:"code"`
+	Name       string `json:"name"`
+	Numeric    string `json:"numeric"`
+	MinorUnits int32  `json:"minor_units"`
+	Symbol     string `json:"symbol"`
+}
+
+var isoCurrencies = map[string]Currency{
+	"AED": {Code: "AED", Name: "UAE Dirham", Numeric: "784", MinorUnits: 2, Symbol: "د.إ"},
+	"AUD": {Code: "AUD", Name: "Australian Dollar", Numeric: "036", MinorUnits: 2, Symbol: "A$"},
+	"BHD": {Code: "BHD", Name: "Bahraini Dinar", Numeric: "048", MinorUnits: 3, Symbol: ".د.ب"},
+	"BRL": {Code: "BRL", Name: "Brazilian Real", Numeric: "986", MinorUnits: 2, Symbol: "R$"},
+	"BTC": {Code: "BTC", Name: "Bitcoin", Numeric: "", MinorUnits: 8, Symbol: "₿"},
+	"CAD": {Code: "CAD", Name: "Canadian Dollar", Numeric: "124", MinorUnits: 2, Symbol: "CA$"},
+	"CHF": {Code: "CHF", Name: "Swiss Franc", Numeric: "756", MinorUnits: 2, Symbol: "CHF"},
+	"CNY": {Code: "CNY", Name: "Yuan Renminbi", Numeric: "156", MinorUnits: 2, Symbol: "¥"},
+	"CZK": {Code: "CZK", Name: "Czech Koruna", Numeric: "203", MinorUnits: 2, Symbol: "Kč"},
+	"DKK": {Code: "DKK", Name: "Danish Krone", Numeric: "208", MinorUnits: 2, Symbol: "kr"},
+	"EUR": {Code: "EUR", Name: "Euro", Numeric: "978", MinorUnits: 2, Symbol: "€"},
+	"GBP": {Code: "GBP", Name: "Pound Sterling", Numeric: "826", MinorUnits: 2, Symbol: "£"},
+	"HKD": {Code: "HKD", Name: "Hong Kong Dollar", Numeric: "344", MinorUnits: 2, Symbol: "HK$"},
+	"HRK": {Code: "HRK", Name: "Kuna", Numeric: "191", MinorUnits: 2, Symbol: "kn"},
+	"HUF": {Code: "HUF", Name: "Forint", Numeric: "348", MinorUnits: 2, Symbol: "Ft"},
+	"IDR": {Code: "IDR", Name: "Rupiah", Numeric: "360", MinorUnits: 2, Symbol: "Rp"},
+	"INR": {Code: "INR", Name: "Indian Rupee", Numeric: "356", MinorUnits: 2, Symbol: "₹"},
+	"JPY": {Code: "JPY", Name: "Yen", Numeric: "392", MinorUnits: 0, Symbol: "¥"},
+	"KRW": {Code: "KRW", Name: "Won", Numeric: "410", MinorUnits: 0, Symbol: "₩"},
+	"KWD": {Code: "KWD", Name: "Kuwaiti Dinar", Numeric: "414", MinorUnits: 3, Symbol: "د.ك"},
+	"MXN": {Code: "MXN", Name: "Mexican Peso", Numeric: "484", MinorUnits: 2, Symbol: "MX$"},
+	"MYR": {Code: "MYR", Name: "Malaysian Ringgit", Numeric: "458", MinorUnits: 2, Symbol: "RM"},
+	"NOK": {Code: "NOK", Name: "Norwegian Krone", Numeric: "578", MinorUnits: 2, Symbol: "kr"},
+	"NZD": {Code: "NZD", Name: "New Zealand Dollar", Numeric: "554", MinorUnits: 2, Symbol: "NZ$"},
+	"PHP": {Code: "PHP", Name: "Philippine Peso", Numeric: "608", MinorUnits: 2, Symbol: "₱"},
+	"PLN": {Code: "PLN", Name: "Zloty", Numeric: "985", MinorUnits: 2, Symbol: "zł"},
+	"RUB": {Code: "RUB", Name: "Russian Ruble", Numeric: "643", MinorUnits: 2, Symbol: "₽"},
+	"SAR": {Code: "SAR", Name: "Saudi Riyal", Numeric: "682", MinorUnits: 2, Symbol: "ر.س"},
+	"SEK": {Code: "SEK", Name: "Swedish Krona", Numeric: "752", MinorUnits: 2, Symbol: "kr"},
+	"SGD": {Code: "SGD", Name: "Singapore Dollar", Numeric: "702", MinorUnits: 2, Symbol: "S$"},
+	"THB": {Code: "THB", Name: "Baht", Numeric: "764", MinorUnits: 2, Symbol: "฿"},
+	"TRY": {Code: "TRY", Name: "Turkish Lira", Numeric: "949", MinorUnits: 2, Symbol: "₺"},
+	"USD": {Code: "USD", Name: "US Dollar", Numeric: "840", MinorUnits: 2, Symbol: "$"},
+	"ZAR": {Code: "ZAR", Name: "Rand", Numeric: "710", MinorUnits: 2, Symbol: "R"},
+}
+
+// CurrencyRegistry holds the configured currencies, narrowed down to the
+// ones the active provider quotes once rates have been loaded.
+type CurrencyRegistry struct {
+	configured map[string]Currency
+	aliases    map[string]string
+	quoted     map[string]bool
+
+	mu sync.RWMutex
+}
+
+func NewCurrencyRegistry(currencies []Currency, aliases map[string]string) *CurrencyRegistry {
+	configured := make(map[string]Currency)
+	for _, currency := range currencies {
+		configured[currency.Code] = currency
+		money.RegisterMinorUnits(currency.Code, currency.MinorUnits)
+	}
+
+	for _, redenomination := range redenominations {
+		legacy := redenomination.Legacy
+		money.RegisterMinorUnits(legacy.Code, legacy.MinorUnits)
+	}
+
+	return &CurrencyRegistry{
+		configured: configured,
+		aliases:    aliases,
+	}
+}
+
+// Normalize upper-cases code and resolves configured aliases, so "rmb"
+// becomes "CNY".
+func (r *CurrencyRegistry) Normalize(code string) string {
+	code = normalizeCode(code)
+	if target, ok := r.aliases[code]; ok {
+		return target
+	}
+
+	return code
+}
+
+// LoadCurrencies resolves the comma-separated codes against the built-in ISO
+// 4217 table. The optional JSON file at path adds or overrides metadata, so
+// codes outside the table can be enabled too.
+func LoadCurrencies(codes, path string) ([]Currency, error) {
+	if codes == "" {
+		codes = defaultSupportedCurrencies
+	}
+
+	metadata := make(map[string]Currency, len(isoCurrencies))
+	for code, currency := range isoCurrencies {
+		metadata[code] = currency
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var overrides []Currency
+		if err := json.Unmarshal(data, &overrides); err != nil {
+			return nil, err
+		}
+
+		for _, currency := range overrides {
+			metadata[currency.Code] = currency
+		}
+	}
+
+	var currencies []Currency
+	for _, code := range strings.Split(codes, ",") {
+		code = normalizeCode(code)
+		if code == "" {
+			continue
+		}
+
+		currency, ok := metadata[code]
+		if !ok {
+			return nil, fmt.Errorf("no metadata for currency %s", code)
+		}
+		currencies = append(currencies, currency)
+	}
+
+	return currencies, nil
+}
+
+// SetQuoted records which currencies the provider returned rates for.
+func (r *CurrencyRegistry) SetQuoted(rates map[string]decimal.Decimal) {
+	quoted := make(map[string]bool, len(rates))
+	for code := range rates {
+		quoted[code] = true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.quoted = quoted
+}
+
+// Lookup returns the metadata for code if it is configured and, once rates
+// are known, quoted by the provider. Legacy currencies are accepted whenever
+// their successor is.
+func (r *CurrencyRegistry) Lookup(code string) (Currency, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if currency, ok := r.configured[code]; ok && r.isQuoted(code) {
+		return currency, true
+	}
+
+	if redenomination, ok := redenominations[code]; ok {
+		if _, ok := r.configured[redenomination.Successor]; ok && r.isQuoted(redenomination.Successor) {
+			return redenomination.Legacy, true
+		}
+	}
+
+	return Currency{}, false
+}
+
+func (r *CurrencyRegistry) isQuoted(code string) bool {
+	return r.quoted == nil || r.quoted[code]
+}
+
+func (r *CurrencyRegistry) IsSupported(code string) bool {
+	_, ok := r.Lookup(code)
+	return ok
+}
+
+// List returns the supported currencies sorted by code.
+func (r *CurrencyRegistry) List() []Currency {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	currencies := make([]Currency, 0, len(r.configured))
+	for code, currency := range r.configured {
+		if !r.isQuoted(code) {
+			continue
+		}
+		currencies = append(currencies, currency)
+	}
+
+	sort.Slice(currencies, func(i, j int) bool {
+		return currencies[i].Code < currencies[j].Code
+	})
+
+	return currencies
+}
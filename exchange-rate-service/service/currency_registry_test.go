@@ -0,0 +1,81 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestLoadCurrenciesDefaults(t *testing.T) {
+	currencies, err := LoadCurrencies("", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(currencies) != 6 {
+		t.Fatalf("Expected 6 default currencies, got %d", len(currencies))
+	}
+
+	registry := NewCurrencyRegistry(currencies, nil)
+	inr, ok := registry.Lookup("INR")
+	if !ok {
+		t.Fatal("Expected INR to be supported")
+	}
+	if inr.Numeric != "356" || inr.MinorUnits != 2 || inr.Symbol != "₹" {
+		t.Errorf("Unexpected INR metadata: %+v", inr)
+	}
+}
+
+func TestLoadCurrenciesWithConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "currencies.json")
+	config := `[{"code": "XAU", "name": "Gold", "numeric": "959", "minor_units": 4, "symbol": "XAU"}]`
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	currencies, err := LoadCurrencies("USD, XAU", path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	registry := NewCurrencyRegistry(currencies, nil)
+	if !registry.IsSupported("XAU") {
+		t.Error("Expected XAU from config to be supported")
+	}
+	if registry.IsSupported("EUR") {
+		t.Error("EUR was not configured and should not be supported")
+	}
+
+	if _, err := LoadCurrencies("USD,ABC", ""); err == nil {
+		t.Error("Expected error for currency without metadata")
+	}
+}
+
+func TestRegistryIntersectsProviderQuotes(t *testing.T) {
+	currencies, _ := LoadCurrencies("USD,EUR,BTC", "")
+	registry := NewCurrencyRegistry(currencies, nil)
+
+	if !registry.IsSupported("BTC") {
+		t.Error("Configured currencies should be supported before rates load")
+	}
+
+	registry.SetQuoted(map[string]decimal.Decimal{
+		"USD": decimal.NewFromInt(1),
+		"EUR": decimal.NewFromFloat(0.92),
+		"INR": decimal.NewFromFloat(83.12),
+	})
+
+	if registry.IsSupported("BTC") {
+		t.Error("BTC is not quoted and should not be supported")
+	}
+	if registry.IsSupported("INR") {
+		t.Error("INR is quoted but not configured and should not be supported")
+	}
+
+	list := registry.List()
+	if len(list) != 2 || list[0].Code != "EUR" || list[1].Code != "USD" {
+		t.Errorf("Unexpected currency list: %+v", list)
+	}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var hundred = decimal.NewFromInt(100)
+
+// RateChange describes how one symbol moved between two dates. ChangePct is
+// relative to StartRate.
+type RateChange struct {
+	StartRate decimal.Decimal
+	EndRate   decimal.Decimal
+	Change    decimal.Decimal
+	ChangePct decimal.Decimal
+}
+
+// Fluctuation holds the change of each symbol against Base from StartDate
+// to EndDate.
+type Fluctuation struct {
+	Base      string
+	StartDate time.Time
+	EndDate   time.Time
+	Rates     map[string]RateChange
+	Provider  string
+
+	// Snapshots are the historical snapshots the rates came from.
+	Snapshots []*RateSnapshot
+}
+
+// GetFluctuation compares the historical rate tables for start and end,
+// re-based to base and limited to symbols.
+func (s *RateFetcherService) GetFluctuation(base string, symbols []string, start, end time.Time) (*Fluctuation, error) {
+	base, symbols, err := s.resolveSymbols(base, symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateDateRange(start, end); err != nil {
+		return nil, err
+	}
+
+	tables, err := s.getRateTables(base, symbols, []time.Time{start, end})
+	if err != nil {
+		return nil, err
+	}
+
+	startRates, endRates := tables[0].Rates, tables[1].Rates
+	rates := make(map[string]RateChange, len(startRates))
+	for symbol, startRate := range startRates {
+		endRate, ok := endRates[symbol]
+		if !ok {
+			continue
+		}
+
+		change := endRate.Sub(startRate)
+		rates[symbol] = RateChange{
+			StartRate: startRate,
+			EndRate:   endRate,
+			Change:    change,
+			ChangePct: change.Div(startRate).Mul(hundred),
+		}
+	}
+
+	return &Fluctuation{
+		Base:      base,
+		StartDate: start,
+		EndDate:   end,
+		Rates:     rates,
+		Provider:  s.apiClient.Name(),
+		Snapshots: tableSnapshots(tables),
+	}, nil
+}
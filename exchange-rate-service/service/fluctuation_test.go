@@ -0,0 +1,50 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestGetFluctuation(t *testing.T) {
+	s := newTestRateFetcher(t, "USD,EUR,INR")
+
+	end := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	start := end.AddDate(0, 0, -30)
+	s.cache.SetHistoricalRates(start, map[string]decimal.Decimal{
+		"USD": decimal.NewFromInt(1),
+		"EUR": decimal.RequireFromString("0.8"),
+		"INR": decimal.NewFromInt(80),
+	})
+	s.cache.SetHistoricalRates(end, map[string]decimal.Decimal{
+		"USD": decimal.NewFromInt(1),
+		"EUR": decimal.RequireFromString("0.8"),
+		"INR": decimal.NewFromInt(84),
+	})
+
+	fluctuation, err := s.GetFluctuation("EUR", []string{"inr"}, start, end)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	inr, ok := fluctuation.Rates["INR"]
+	if !ok {
+		t.Fatal("Expected INR in fluctuation")
+	}
+
+	expected := map[string]struct {
+		got  decimal.Decimal
+		want string
+	}{
+		"start_rate": {inr.StartRate, "100"},
+		"end_rate":   {inr.EndRate, "105"},
+		"change":     {inr.Change, "5"},
+		"change_pct": {inr.ChangePct, "5"},
+	}
+	for name, tt := range expected {
+		if !tt.got.Equal(decimal.RequireFromString(tt.want)) {
+			t.Errorf("%s = %s, expected %s", name, tt.got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// immutableMaxAge is how long responses built only from past days' rates
+// may be cached. Those rates never change.
+const immutableMaxAge = 365 * 24 * time.Hour
+
+// Freshness describes how long a response built from rate snapshots stays
+// valid, for HTTP caching.
+type Freshness struct {
+	// ETag is a weak entity tag: the same snapshots always give the same
+	// tag, though fields such as cache_age_seconds still move.
+	ETag         string
+	LastModified time.Time
+	MaxAge       time.Duration
+	Immutable    bool
+}
+
+// Freshness derives caching metadata from the snapshots a response was
+// built from. Latest snapshots are tagged by version, modified when the
+// cache was last updated and valid until the next scheduled refresh.
+// Snapshots for days before today are immutable.
+func (s *RateFetcherService) Freshness(snapshots ...*RateSnapshot) Freshness {
+	freshness := Freshness{Immutable: true, MaxAge: immutableMaxAge}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	tags := make([]string, len(snapshots))
+	for i, snapshot := range snapshots {
+		if snapshot.UpdatedAt.After(freshness.LastModified) {
+			freshness.LastModified = snapshot.UpdatedAt
+		}
+
+		if snapshot.Date == nil {
+			tags[i] = "v" + strconv.FormatUint(snapshot.Version, 10)
+		} else {
+			tags[i] = snapshot.Date.Format("2006-01-02")
+			if snapshot.Date.UTC().Before(today) {
+				continue
+			}
+			// Today's historical rates are fixed once fetched, until the
+			// cache drops them.
+			tags[i] += "." + strconv.FormatInt(snapshot.UpdatedAt.Unix(), 10)
+		}
+
+		freshness.Immutable = false
+		freshness.MaxAge = min(freshness.MaxAge, s.untilNextRefresh())
+	}
+
+	tag := strings.Join(tags, "+")
+	if len(snapshots) > 1 {
+		sum := sha256.Sum256([]byte(tag))
+		tag = hex.EncodeToString(sum[:8])
+	}
+	freshness.ETag = `W/"` + tag + `"`
+
+	return freshness
+}
+
+// untilNextRefresh is the time left before the scheduled refresh, or zero
+// if refreshes are not scheduled.
+func (s *RateFetcherService) untilNextRefresh() time.Duration {
+	status := s.scheduler.get()
+	if !status.Running {
+		return 0
+	}
+
+	return max(time.Until(status.NextRun), 0)
+}
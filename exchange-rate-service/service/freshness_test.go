@@ -0,0 +1,48 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFreshness(t *testing.T) {
+	s := newTestRateFetcher(t, "USD,EUR")
+	s.scheduler.start(time.Hour)
+
+	updated := time.Now().Add(-10 * time.Minute)
+	latest := &RateSnapshot{UpdatedAt: updated, Version: 42}
+
+	freshness := s.Freshness(latest)
+	if freshness.ETag != `W/"v42"` {
+		t.Errorf("ETag = %s, expected W/\"v42\"", freshness.ETag)
+	}
+	if !freshness.LastModified.Equal(updated) {
+		t.Errorf("LastModified = %s, expected %s", freshness.LastModified, updated)
+	}
+	if freshness.Immutable || freshness.MaxAge <= 59*time.Minute || freshness.MaxAge > time.Hour {
+		t.Errorf("Latest freshness = %+v, expected about an hour until the next refresh", freshness)
+	}
+
+	past := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -3)
+	historical := &RateSnapshot{Date: &past, UpdatedAt: updated}
+
+	freshness = s.Freshness(historical)
+	if freshness.ETag != `W/"`+past.Format("2006-01-02")+`"` || !freshness.Immutable || freshness.MaxAge != immutableMaxAge {
+		t.Errorf("Historical freshness = %+v, expected immutable", freshness)
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	freshness = s.Freshness(historical, &RateSnapshot{Date: &today, UpdatedAt: updated})
+	if freshness.Immutable || freshness.MaxAge > time.Hour {
+		t.Errorf("Range ending today = %+v, expected not immutable", freshness)
+	}
+	if strings.Contains(freshness.ETag, "+") || freshness.ETag == s.Freshness(historical, historical).ETag {
+		t.Errorf("Range ETag %s should be a hash that differs per range", freshness.ETag)
+	}
+
+	s.scheduler = scheduler{}
+	if freshness := s.Freshness(latest); freshness.MaxAge != 0 {
+		t.Errorf("MaxAge without scheduled refreshes = %s, expected 0", freshness.MaxAge)
+	}
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxRateAge = 3 * time.Hour
+	refreshInterval   = 1 * time.Hour
+	// unhealthyAfter is how many calls in a row must fail before a provider
+	// is marked unhealthy, so that one dropped request does not fail
+	// readiness.
+	unhealthyAfter = 3
+)
+
+// ProviderState tracks the health of a rate provider from the outcome of
+// its latest-rate calls. A provider is healthy once a call has succeeded,
+// until unhealthyAfter calls in a row fail.
+type ProviderState struct {
+	Name                string
+	Healthy             bool
+	LastSuccess         time.Time
+	LastFailure         time.Time
+	LastError           string
+	ConsecutiveFailures int
+}
+
+func (p *ProviderState) record(err error, at time.Time) {
+	if err != nil {
+		p.LastFailure = at
+		p.LastError = err.Error()
+		p.ConsecutiveFailures++
+		if p.ConsecutiveFailures >= unhealthyAfter {
+			p.Healthy = false
+		}
+		return
+	}
+
+	p.Healthy = true
+	p.LastSuccess = at
+	p.ConsecutiveFailures = 0
+}
+
+// SchedulerStatus describes the background refresh of the latest rates.
+type SchedulerStatus struct {
+	Running   bool
+	Interval  time.Duration
+	LastRun   time.Time
+	LastError string
+	NextRun   time.Time
+}
+
+type scheduler struct {
+	status SchedulerStatus
+	mu     sync.Mutex
+}
+
+func (s *scheduler) start(interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status.Running = true
+	s.status.Interval = interval
+	s.status.NextRun = time.Now().Add(interval)
+}
+
+func (s *scheduler) ran(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.status.LastRun = now
+	s.status.NextRun = now.Add(s.status.Interval)
+	s.status.LastError = ""
+	if err != nil {
+		s.status.LastError = err.Error()
+	}
+}
+
+func (s *scheduler) get() SchedulerStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.status
+}
+
+// HealthReport summarises whether the service can answer from fresh rates.
+// Problems lists why it is not ready and is empty when Ready is true.
+type HealthReport struct {
+	Ready       bool
+	Problems    []string
+	LastUpdated time.Time
+	CacheAge    time.Duration
+	MaxRateAge  time.Duration
+	Providers   []ProviderState
+	Scheduler   SchedulerStatus
+}
+
+// Health reports the age of the latest rates, the provider states and the
+// refresh scheduler. The service is ready when rates have been loaded within
+// maxRateAge and at least one provider is healthy.
+func (s *RateFetcherService) Health() *HealthReport {
+	lastUpdated := s.cache.GetLastUpdated()
+	report := &HealthReport{
+		LastUpdated: lastUpdated,
+		MaxRateAge:  s.maxRateAge,
+		Providers:   []ProviderState{s.apiClient.State()},
+		Scheduler:   s.scheduler.get(),
+	}
+
+	if lastUpdated.IsZero() {
+		report.Problems = append(report.Problems, "latest rates have not been loaded")
+	} else {
+		report.CacheAge = time.Since(lastUpdated)
+		if report.CacheAge > s.maxRateAge {
+			report.Problems = append(report.Problems, "latest rates are older than "+s.maxRateAge.String())
+		}
+	}
+
+	healthy := false
+	for _, provider := range report.Providers {
+		healthy = healthy || provider.Healthy
+	}
+	if !healthy {
+		report.Problems = append(report.Problems, "no rate provider is healthy")
+	}
+
+	report.Ready = len(report.Problems) == 0
+
+	return report
+}
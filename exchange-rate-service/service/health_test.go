@@ -0,0 +1,57 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestHealth(t *testing.T) {
+	s := newTestRateFetcher(t, "USD,EUR")
+	s.apiClient = &APIClient{}
+	s.maxRateAge = time.Hour
+
+	s.apiClient.record("live", time.Now(), errors.New("connection refused"))
+	report := s.Health()
+	if report.Ready {
+		t.Fatal("Expected not ready before rates are loaded")
+	}
+	if len(report.Problems) != 2 {
+		t.Errorf("Expected missing rates and unhealthy provider, got %v", report.Problems)
+	}
+	if report.Providers[0].ConsecutiveFailures != 1 || report.Providers[0].LastError != "connection refused" {
+		t.Errorf("Unexpected provider state: %+v", report.Providers[0])
+	}
+
+	s.apiClient.record("live", time.Now(), nil)
+	s.cache.SetLatestRates(map[string]decimal.Decimal{"USD": decimal.NewFromInt(1)})
+	report = s.Health()
+	if !report.Ready {
+		t.Fatalf("Expected ready, got problems %v", report.Problems)
+	}
+
+	s.apiClient.record("historical", time.Now(), errors.New("no rates for date"))
+	for i := 1; i < unhealthyAfter; i++ {
+		s.apiClient.record("live", time.Now(), errors.New("connection refused"))
+	}
+	if report = s.Health(); !report.Ready {
+		t.Fatalf("Expected ready below the failure threshold, got problems %v", report.Problems)
+	}
+	if report.Providers[0].ConsecutiveFailures != unhealthyAfter-1 {
+		t.Errorf("Expected historical failures not to count, got %+v", report.Providers[0])
+	}
+
+	s.apiClient.record("live", time.Now(), errors.New("connection refused"))
+	if report = s.Health(); report.Ready {
+		t.Error("Expected not ready once the failure threshold is reached")
+	}
+
+	s.apiClient.record("live", time.Now(), nil)
+	s.maxRateAge = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	if report = s.Health(); report.Ready {
+		t.Error("Expected not ready once rates exceed the maximum age")
+	}
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+)
+
+const (
+	defaultJWKSRefresh = time.Hour
+	// jwksMinRefetch bounds how often a token with an unknown key ID can
+	// make the key set reload, so such tokens cannot hammer the JWKS.
+	jwksMinRefetch  = time.Minute
+	jwksHTTPTimeout = 10 * time.Second
+	jwksMaxBytes    = 1 << 20
+)
+
+// jsonWebKey is a public key in a JWKS, as defined by RFC 7517. Only RSA
+// and EC signing keys are used.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS reads the signing keys of a JWKS document by key ID. Keys of
+// other types, curves or uses, or that cannot be decoded, are skipped and
+// logged, so one unusable key does not discard the rest.
+func parseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var doc struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey)
+	for _, jwk := range doc.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			log.Printf("Skipping JWKS key %q with use %q", jwk.Kid, jwk.Use)
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			log.Printf("Skipping JWKS key %q: %v", jwk.Kid, err)
+			continue
+		}
+
+		keys[jwk.Kid] = key
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("JWKS has no usable RSA or EC signing keys")
+	}
+
+	return keys, nil
+}
+
+// publicKey decodes k, failing for unsupported key types and curves.
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeJWKInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeJWKInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 3 {
+			return nil, fmt.Errorf("invalid RSA exponent")
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+
+		x, err := decodeJWKInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeJWKInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+func decodeJWKInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(data) == 0 {
+		return nil, fmt.Errorf("invalid base64url integer %q", value)
+	}
+
+	return new(big.Int).SetBytes(data), nil
+}
+
+// JWKSKeySet caches the keys of a JWKS file or URL. They are reloaded
+// every refresh interval, and sooner when a token names an unknown key, so
+// rotated keys are picked up. If a reload fails the previous keys are kept.
+type JWKSKeySet struct {
+	source      string
+	load        func(ctx context.Context) ([]byte, error)
+	refresh     time.Duration
+	keys        map[string]crypto.PublicKey
+	loadedAt    time.Time
+	lastAttempt time.Time
+	now         func() time.Time
+	// loading is closed when the reload in flight finishes, and is nil
+	// when there is none.
+	loading chan struct{}
+	mu      sync.Mutex
+}
+
+// NewJWKSFileKeySet reads the keys from the JWKS at path.
+func NewJWKSFileKeySet(path string, refresh time.Duration) (*JWKSKeySet, error) {
+	s := newJWKSKeySet(path, func(context.Context) ([]byte, error) {
+		return os.ReadFile(path)
+	}, refresh)
+
+	if err := s.reload(context.Background(), s.now()); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// NewJWKSURLKeySet fetches the keys from the JWKS at url when they are
+// first needed.
+func NewJWKSURLKeySet(url string, refresh time.Duration) *JWKSKeySet {
+	client := &http.Client{Timeout: jwksHTTPTimeout}
+
+	return newJWKSKeySet(url, func(ctx context.Context) ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("JWKS returned status %d", resp.StatusCode)
+		}
+
+		return io.ReadAll(io.LimitReader(resp.Body, jwksMaxBytes))
+	}, refresh)
+}
+
+func newJWKSKeySet(source string, load func(ctx context.Context) ([]byte, error), refresh time.Duration) *JWKSKeySet {
+	return &JWKSKeySet{
+		source:  source,
+		load:    load,
+		refresh: refresh,
+		now:     time.Now,
+	}
+}
+
+// Key returns the key with ID kid. A token without a key ID is accepted
+// when the set has a single key. When the keys need reloading, Key waits
+// for the reload until ctx is done; callers share a single reload, which
+// carries on if they give up.
+func (s *JWKSKeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	now := s.now()
+	_, found := s.lookup(kid)
+	stale := s.keys == nil || now.Sub(s.loadedAt) >= s.refresh
+	loading := s.loading
+	if loading == nil && (stale || !found) && now.Sub(s.lastAttempt) >= jwksMinRefetch {
+		loading = s.startReload(now)
+	}
+	s.mu.Unlock()
+
+	if loading != nil && (stale || !found) {
+		select {
+		case <-loading:
+		case <-ctx.Done():
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, found := s.lookup(kid)
+	if s.keys == nil {
+		return nil, appErrors.SigningKeysUnavailableError(fmt.Errorf("no keys loaded from %s", s.source))
+	}
+	if !found {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	return key, nil
+}
+
+// lookup finds kid in the loaded keys. The caller holds s.mu.
+func (s *JWKSKeySet) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+// startReload reloads the keys in the background, outside the lock and
+// detached from any request, and returns a channel closed once it is
+// done. The caller holds s.mu.
+func (s *JWKSKeySet) startReload(now time.Time) chan struct{} {
+	loading := make(chan struct{})
+	s.loading = loading
+	s.lastAttempt = now
+
+	go func() {
+		keys, err := s.fetch(context.Background())
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if err != nil {
+			log.Printf("Failed to load JWKS from %s: %v", s.source, err)
+		} else {
+			s.keys = keys
+			s.loadedAt = now
+		}
+		s.loading = nil
+		close(loading)
+	}()
+
+	return loading
+}
+
+// reload replaces the keys from the source, keeping them on failure. It is
+// only used before s is shared.
+func (s *JWKSKeySet) reload(ctx context.Context, now time.Time) error {
+	s.lastAttempt = now
+
+	keys, err := s.fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.keys = keys
+	s.loadedAt = now
+	return nil
+}
+
+// fetch loads and parses the JWKS.
+func (s *JWKSKeySet) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	data, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseJWKS(data)
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+)
+
+func encodeJWKInt(n *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(n.Bytes())
+}
+
+// testJWKS encodes public keys by key ID as a JWKS document.
+func testJWKS(t *testing.T, keys map[string]crypto.PublicKey) []byte {
+	t.Helper()
+
+	var doc struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	for kid, key := range keys {
+		switch key := key.(type) {
+		case *rsa.PublicKey:
+			doc.Keys = append(doc.Keys, jsonWebKey{Kty: "RSA", Kid: kid, Use: "sig",
+				N: encodeJWKInt(key.N), E: encodeJWKInt(big.NewInt(int64(key.E)))})
+		case *ecdsa.PublicKey:
+			doc.Keys = append(doc.Keys, jsonWebKey{Kty: "EC", Kid: kid, Use: "sig", Crv: key.Curve.Params().Name,
+				X: encodeJWKInt(key.X), Y: encodeJWKInt(key.Y)})
+		}
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return data
+}
+
+func TestParseJWKS(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	keys, err := parseJWKS(testJWKS(t, map[string]crypto.PublicKey{"rsa": &rsaKey.PublicKey, "ec": &ecKey.PublicKey}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !rsaKey.PublicKey.Equal(keys["rsa"]) || !ecKey.PublicKey.Equal(keys["ec"]) {
+		t.Errorf("Parsed keys do not match the generated ones")
+	}
+
+	for _, doc := range []string{
+		`not json`,
+		`{"keys":[]}`,
+		`{"keys":[{"kty":"oct","k":"c2VjcmV0"}]}`,
+		`{"keys":[{"kty":"RSA","kid":"enc","use":"enc","n":"AQAB","e":"AQAB"}]}`,
+		`{"keys":[{"kty":"EC","crv":"P-192","x":"AQ","y":"AQ"}]}`,
+	} {
+		if _, err := parseJWKS([]byte(doc)); err == nil {
+			t.Errorf("parseJWKS(%s) expected an error", doc)
+		}
+	}
+}
+
+func TestParseJWKSSkipsUnusableKeys(t *testing.T) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	doc := struct {
+		Keys []jsonWebKey `json:"keys"`
+	}{Keys: []jsonWebKey{
+		{Kty: "oct", Kid: "hmac"},
+		{Kty: "RSA", Kid: "enc", Use: "enc", N: "AQAB", E: "AQAB"},
+		{Kty: "EC", Kid: "p192", Crv: "P-192", X: "AQ", Y: "AQ"},
+		{Kty: "OKP", Kid: "ed", Crv: "Ed25519", X: "AQ"},
+		{Kty: "EC", Kid: "ec", Use: "sig", Crv: "P-256", X: encodeJWKInt(ecKey.X), Y: encodeJWKInt(ecKey.Y)},
+	}}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	keys, err := parseJWKS(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(keys) != 1 || !ecKey.PublicKey.Equal(keys["ec"]) {
+		t.Errorf("Parsed keys %v, expected only the EC signing key", keys)
+	}
+}
+
+func TestJWKSFileKeySet(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, testJWKS(t, map[string]crypto.PublicKey{"one": &key.PublicKey}), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	keys, err := NewJWKSFileKeySet(path, time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, kid := range []string{"one", ""} {
+		if got, err := keys.Key(context.Background(), kid); err != nil || !key.PublicKey.Equal(got) {
+			t.Errorf("Key(%q) = %v, %v, expected the only key", kid, got, err)
+		}
+	}
+
+	if _, err := NewJWKSFileKeySet(filepath.Join(t.TempDir(), "missing.json"), time.Hour); err == nil {
+		t.Error("Expected a missing JWKS file to fail")
+	}
+}
+
+func TestJWKSURLKeySetRefetchesUnknownKeys(t *testing.T) {
+	first, _ := rsa.GenerateKey(rand.Reader, 2048)
+	second, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	var doc atomic.Value
+	doc.Store(testJWKS(t, map[string]crypto.PublicKey{"first": &first.PublicKey}))
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if data := doc.Load().([]byte); data != nil {
+			w.Write(data)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	now := time.Date(2025, 11, 28, 12, 0, 0, 0, time.UTC)
+	keys := NewJWKSURLKeySet(server.URL, time.Hour)
+	keys.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if _, err := keys.Key(ctx, "first"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	doc.Store(testJWKS(t, map[string]crypto.PublicKey{"first": &first.PublicKey, "second": &second.PublicKey}))
+	if _, err := keys.Key(ctx, "second"); err == nil {
+		t.Error("Expected an unknown key not to refetch within a minute")
+	}
+
+	now = now.Add(jwksMinRefetch)
+	if got, err := keys.Key(ctx, "second"); err != nil || !second.PublicKey.Equal(got) {
+		t.Errorf("Key(second) = %v, %v, expected the rotated key", got, err)
+	}
+	if fetches.Load() != 2 {
+		t.Errorf("Fetched the JWKS %d times, expected 2", fetches.Load())
+	}
+
+	doc.Store([]byte(nil))
+	now = now.Add(2 * time.Hour)
+	if _, err := keys.Key(ctx, "first"); err != nil {
+		t.Errorf("Expected the previous keys to be kept when a refresh fails, got %v", err)
+	}
+}
+
+func TestJWKSURLKeySetUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	_, err := NewJWKSURLKeySet(server.URL, time.Hour).Key(context.Background(), "any")
+	expectCode(t, err, appErrors.ErrSigningKeysUnavailable)
+}
+
+func TestJWKSURLKeySetSharesDetachedReload(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	doc := testJWKS(t, map[string]crypto.PublicKey{"one": &key.PublicKey})
+
+	release := make(chan struct{})
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		<-release
+		w.Write(doc)
+	}))
+	defer server.Close()
+
+	keys := NewJWKSURLKeySet(server.URL, time.Hour)
+
+	// A caller that gives up does not fail the reload for the others.
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := keys.Key(cancelled, "one")
+	expectCode(t, err, appErrors.ErrSigningKeysUnavailable)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := keys.Key(context.Background(), "one")
+			errs <- err
+		}()
+	}
+
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+	if fetches.Load() != 1 {
+		t.Errorf("Fetched the JWKS %d times, expected one shared fetch", fetches.Load())
+	}
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+)
+
+const (
+	defaultRolesClaim = "roles"
+	// tokenLeeway allows for clock skew between the issuer and the service
+	// when checking exp, nbf and iat.
+	tokenLeeway = 30 * time.Second
+)
+
+// tokenSigningMethods are the asymmetric algorithms a JWKS can verify.
+// Symmetric and unsigned tokens are always rejected.
+var tokenSigningMethods = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+}
+
+// Principal is the caller identified by a bearer token.
+type Principal struct {
+	Subject string
+	Roles   []string
+	Scopes  []Scope
+}
+
+func (p Principal) HasScope(scope Scope) bool {
+	for _, granted := range p.Scopes {
+		if granted == scope || granted == ScopeAdmin {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TokenVerifier checks OIDC bearer tokens against the issuer's JWKS and
+// maps the roles they carry to scopes.
+type TokenVerifier struct {
+	keys       *JWKSKeySet
+	parser     *jwt.Parser
+	rolesClaim []string
+	roleScopes map[string][]Scope
+}
+
+// NewTokenVerifier reads the JWKS from OIDC_JWKS_URL or OIDC_JWKS_FILE, and
+// the issuer and audience tokens must name from OIDC_ISSUER and
+// OIDC_AUDIENCE. Roles are read from the OIDC_ROLES_CLAIM claim and mapped
+// to scopes by OIDC_ROLE_SCOPES. It returns nil when no JWKS is configured,
+// leaving bearer tokens disabled.
+func NewTokenVerifier() *TokenVerifier {
+	jwksURL := os.Getenv("OIDC_JWKS_URL")
+	jwksFile := os.Getenv("OIDC_JWKS_FILE")
+	if jwksURL == "" && jwksFile == "" {
+		return nil
+	}
+	if jwksURL != "" && jwksFile != "" {
+		panic("set only one of OIDC_JWKS_URL and OIDC_JWKS_FILE")
+	}
+
+	issuer := os.Getenv("OIDC_ISSUER")
+	audience := os.Getenv("OIDC_AUDIENCE")
+	if issuer == "" || audience == "" {
+		panic("OIDC_ISSUER and OIDC_AUDIENCE are required with a JWKS")
+	}
+
+	refresh := defaultJWKSRefresh
+	if value := os.Getenv("OIDC_JWKS_REFRESH"); value != "" {
+		var err error
+		refresh, err = time.ParseDuration(value)
+		if err != nil || refresh <= 0 {
+			panic(fmt.Sprintf("invalid OIDC_JWKS_REFRESH: %q", value))
+		}
+	}
+
+	roleScopes, err := ParseRoleScopes(os.Getenv("OIDC_ROLE_SCOPES"))
+	if err != nil {
+		panic(fmt.Sprintf("invalid OIDC_ROLE_SCOPES: %v", err))
+	}
+
+	rolesClaim := defaultRolesClaim
+	if value := os.Getenv("OIDC_ROLES_CLAIM"); value != "" {
+		rolesClaim = value
+	}
+
+	var keys *JWKSKeySet
+	if jwksFile != "" {
+		keys, err = NewJWKSFileKeySet(jwksFile, refresh)
+		if err != nil {
+			panic(fmt.Sprintf("failed to load OIDC_JWKS_FILE: %v", err))
+		}
+	} else {
+		keys = NewJWKSURLKeySet(jwksURL, refresh)
+	}
+
+	return newTokenVerifier(keys, issuer, audience, rolesClaim, roleScopes)
+}
+
+func newTokenVerifier(keys *JWKSKeySet, issuer, audience, rolesClaim string, roleScopes map[string][]Scope) *TokenVerifier {
+	return &TokenVerifier{
+		keys: keys,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods(tokenSigningMethods),
+			jwt.WithIssuer(issuer),
+			jwt.WithAudience(audience),
+			jwt.WithExpirationRequired(),
+			jwt.WithIssuedAt(),
+			jwt.WithLeeway(tokenLeeway),
+		),
+		rolesClaim: strings.Split(rolesClaim, "."),
+		roleScopes: roleScopes,
+	}
+}
+
+// ParseRoleScopes reads comma-separated ROLE=SCOPE pairs. A role listed
+// more than once grants each scope. An empty spec maps each scope name, as
+// a role, to itself.
+func ParseRoleScopes(spec string) (map[string][]Scope, error) {
+	roleScopes := make(map[string][]Scope)
+	if spec == "" {
+		for _, scope := range Scopes {
+			roleScopes[string(scope)] = []Scope{scope}
+		}
+		return roleScopes, nil
+	}
+
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		role, name, ok := strings.Cut(pair, "=")
+		if !ok || role == "" {
+			return nil, fmt.Errorf("invalid role mapping %q, expected ROLE=SCOPE", pair)
+		}
+
+		scope, ok := parseScope(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown scope %q for role %s", name, role)
+		}
+
+		roleScopes[role] = append(roleScopes[role], scope)
+	}
+
+	return roleScopes, nil
+}
+
+// Verify checks the signature, issuer, audience and expiry of token, and
+// that its roles grant scope.
+func (v *TokenVerifier) Verify(ctx context.Context, token string, scope Scope) (Principal, error) {
+	claims := jwt.MapClaims{}
+	_, err := v.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.keys.Key(ctx, kid)
+	})
+	if err != nil {
+		var customErr *appErrors.CustomError
+		if errors.As(err, &customErr) {
+			return Principal{}, customErr
+		}
+		return Principal{}, appErrors.InvalidTokenError(err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return Principal{}, appErrors.InvalidTokenError(errors.New("token has no subject"))
+	}
+
+	principal := Principal{
+		Subject: subject,
+		Roles:   claimRoles(claims, v.rolesClaim),
+	}
+	for _, role := range principal.Roles {
+		principal.Scopes = append(principal.Scopes, v.roleScopes[role]...)
+	}
+
+	if !principal.HasScope(scope) {
+		return principal, appErrors.InsufficientScopeError(string(scope))
+	}
+
+	return principal, nil
+}
+
+// claimRoles reads the roles at path, a claim name or a dotted path into
+// nested objects such as realm_access.roles. Roles may be an array of
+// strings or a space-separated string, like the OAuth scope claim.
+func claimRoles(claims jwt.MapClaims, path []string) []string {
+	var value any = map[string]any(claims)
+	for _, name := range path {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[name]
+	}
+
+	switch roles := value.(type) {
+	case string:
+		return strings.Fields(roles)
+	case []any:
+		names := make([]string, 0, len(roles))
+		for _, role := range roles {
+			if name, ok := role.(string); ok {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+
+	return nil
+}
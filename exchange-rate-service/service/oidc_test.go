@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+)
+
+const (
+	testIssuer   = "https://issuer.example.com"
+	testAudience = "exchange-rate-service"
+)
+
+// newTestTokenVerifier trusts a freshly generated RSA key, served from a
+// JWKS file as key ID "test", and returns the private key to sign with.
+func newTestTokenVerifier(t *testing.T, rolesClaim, roleScopes string) (*TokenVerifier, *rsa.PrivateKey) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, testJWKS(t, map[string]crypto.PublicKey{"test": &key.PublicKey}), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	t.Setenv("OIDC_JWKS_FILE", path)
+	t.Setenv("OIDC_JWKS_URL", "")
+	t.Setenv("OIDC_ISSUER", testIssuer)
+	t.Setenv("OIDC_AUDIENCE", testAudience)
+	t.Setenv("OIDC_ROLES_CLAIM", rolesClaim)
+	t.Setenv("OIDC_ROLE_SCOPES", roleScopes)
+
+	return NewTokenVerifier(), key
+}
+
+func signTestToken(t *testing.T, method jwt.SigningMethod, key any, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = "test"
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	return signed
+}
+
+func testClaims(overrides jwt.MapClaims) jwt.MapClaims {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":   testIssuer,
+		"aud":   testAudience,
+		"sub":   "svc-ledger",
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+		"roles": []string{"rates"},
+	}
+	for name, value := range overrides {
+		if value == nil {
+			delete(claims, name)
+			continue
+		}
+		claims[name] = value
+	}
+
+	return claims
+}
+
+func TestNewTokenVerifierDisabledWithoutJWKS(t *testing.T) {
+	t.Setenv("OIDC_JWKS_FILE", "")
+	t.Setenv("OIDC_JWKS_URL", "")
+
+	if v := NewTokenVerifier(); v != nil {
+		t.Errorf("Expected no verifier without a JWKS, got %+v", v)
+	}
+}
+
+func TestTokenVerifierVerify(t *testing.T) {
+	v, key := newTestTokenVerifier(t, "", "")
+	otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ctx := context.Background()
+
+	principal, err := v.Verify(ctx, signTestToken(t, jwt.SigningMethodRS256, key, testClaims(nil)), ScopeRates)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if principal.Subject != "svc-ledger" || len(principal.Scopes) != 1 || principal.Scopes[0] != ScopeRates {
+		t.Errorf("Principal = %+v, expected svc-ledger with the rates scope", principal)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		scope Scope
+		code  appErrors.ErrorCode
+	}{
+		{"expired", signTestToken(t, jwt.SigningMethodRS256, key, testClaims(jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()})), ScopeRates, appErrors.ErrInvalidToken},
+		{"no expiry", signTestToken(t, jwt.SigningMethodRS256, key, testClaims(jwt.MapClaims{"exp": nil})), ScopeRates, appErrors.ErrInvalidToken},
+		{"other issuer", signTestToken(t, jwt.SigningMethodRS256, key, testClaims(jwt.MapClaims{"iss": "https://evil.example.com"})), ScopeRates, appErrors.ErrInvalidToken},
+		{"other audience", signTestToken(t, jwt.SigningMethodRS256, key, testClaims(jwt.MapClaims{"aud": "billing"})), ScopeRates, appErrors.ErrInvalidToken},
+		{"untrusted key", signTestToken(t, jwt.SigningMethodRS256, otherKey, testClaims(nil)), ScopeRates, appErrors.ErrInvalidToken},
+		{"symmetric", signTestToken(t, jwt.SigningMethodHS256, []byte("secret"), testClaims(nil)), ScopeRates, appErrors.ErrInvalidToken},
+		{"unsigned", signTestToken(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, testClaims(nil)), ScopeRates, appErrors.ErrInvalidToken},
+		{"no subject", signTestToken(t, jwt.SigningMethodRS256, key, testClaims(jwt.MapClaims{"sub": nil})), ScopeRates, appErrors.ErrInvalidToken},
+		{"malformed", "not.a.token", ScopeRates, appErrors.ErrInvalidToken},
+		{"missing role", signTestToken(t, jwt.SigningMethodRS256, key, testClaims(nil)), ScopeConvert, appErrors.ErrInsufficientScope},
+	}
+
+	for _, tt := range tests {
+		_, err := v.Verify(ctx, tt.token, tt.scope)
+		if err == nil {
+			t.Errorf("%s: expected %s", tt.name, tt.code)
+			continue
+		}
+		expectCode(t, err, tt.code)
+	}
+}
+
+func TestTokenVerifierMapsRoles(t *testing.T) {
+	v, key := newTestTokenVerifier(t, "realm_access.roles", "fx-trader=convert,fx-trader=rates,fx-ops=admin")
+	ctx := context.Background()
+
+	trader := signTestToken(t, jwt.SigningMethodRS256, key, testClaims(jwt.MapClaims{
+		"roles":        nil,
+		"realm_access": map[string]any{"roles": []string{"fx-trader", "unmapped"}},
+	}))
+	for _, scope := range []Scope{ScopeConvert, ScopeRates} {
+		if _, err := v.Verify(ctx, trader, scope); err != nil {
+			t.Errorf("Trader with %s scope: unexpected error %v", scope, err)
+		}
+	}
+	if _, err := v.Verify(ctx, trader, ScopeAdmin); err == nil {
+		t.Error("Expected the trader role not to grant admin")
+	}
+
+	ops := signTestToken(t, jwt.SigningMethodRS256, key, testClaims(jwt.MapClaims{
+		"roles":        nil,
+		"realm_access": map[string]any{"roles": "fx-ops"},
+	}))
+	if _, err := v.Verify(ctx, ops, ScopeConvert); err != nil {
+		t.Errorf("Expected admin to grant every scope, got %v", err)
+	}
+
+	direct := signTestToken(t, jwt.SigningMethodRS256, key, testClaims(nil))
+	if _, err := v.Verify(ctx, direct, ScopeRates); err == nil {
+		t.Error("Expected scope names not to be roles once OIDC_ROLE_SCOPES is set")
+	}
+}
+
+func TestParseRoleScopes(t *testing.T) {
+	for _, spec := range []string{"reader", "=rates", "reader=write"} {
+		if _, err := ParseRoleScopes(spec); err == nil {
+			t.Errorf("ParseRoleScopes(%q) expected an error", spec)
+		}
+	}
+
+	roleScopes, err := ParseRoleScopes("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(roleScopes) != len(Scopes) || roleScopes["admin"][0] != ScopeAdmin {
+		t.Errorf("Default role scopes = %v, expected each scope as a role", roleScopes)
+	}
+}
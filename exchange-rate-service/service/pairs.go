@@ -0,0 +1,110 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+)
+
+// Pair is a currency pair such as EUR/INR, quoted as units of To per unit
+// of From.
+type Pair struct {
+	From string
+	To   string
+}
+
+func (p Pair) String() string {
+	return p.From + "/" + p.To
+}
+
+// MarshalText encodes the pair as "FROM/TO".
+func (p Pair) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText decodes "FROM/TO" without checking that the currencies are
+// supported; use ParsePair for user input.
+func (p *Pair) UnmarshalText(text []byte) error {
+	from, to, ok := strings.Cut(string(text), "/")
+	if !ok {
+		return appErrors.InvalidPairError(string(text))
+	}
+
+	p.From, p.To = from, to
+	return nil
+}
+
+// ParsePair parses "FROM/TO", normalizing both codes and checking that they
+// are distinct and supported.
+func (s *RateFetcherService) ParsePair(value string) (Pair, error) {
+	from, to, ok := strings.Cut(value, "/")
+	if !ok || strings.TrimSpace(from) == "" || strings.TrimSpace(to) == "" {
+		return Pair{}, appErrors.InvalidPairError(value)
+	}
+
+	pair := Pair{From: s.currencies.Normalize(from), To: s.currencies.Normalize(to)}
+	if pair.From == pair.To {
+		return Pair{}, appErrors.InvalidPairError(value)
+	}
+	if !s.currencies.IsSupported(pair.From) {
+		return Pair{}, appErrors.UnsupportedCurrencyError(pair.From)
+	}
+	if !s.currencies.IsSupported(pair.To) {
+		return Pair{}, appErrors.UnsupportedCurrencyError(pair.To)
+	}
+
+	return pair, nil
+}
+
+// PairSubscription follows the rate of a pair and decides which refreshes
+// are worth reporting. Threshold is the minimum change, in percent, from
+// the last reported rate; zero reports every change.
+type PairSubscription struct {
+	Pair      Pair
+	Threshold decimal.Decimal
+
+	last decimal.Decimal
+}
+
+// NewPairSubscription returns a subscription whose last reported rate is
+// rate.
+func NewPairSubscription(pair Pair, threshold string, rate decimal.Decimal) (*PairSubscription, error) {
+	subscription := &PairSubscription{Pair: pair, last: rate}
+
+	if threshold != "" {
+		value, err := decimal.NewFromString(threshold)
+		if err != nil || value.IsNegative() {
+			return nil, appErrors.InvalidThresholdError()
+		}
+		subscription.Threshold = value
+	}
+
+	return subscription, nil
+}
+
+// Last returns the last reported rate.
+func (p *PairSubscription) Last() decimal.Decimal {
+	return p.last
+}
+
+// Update reports whether rate moved beyond the threshold since the last
+// reported rate, and if so records it as reported. It returns the percentage
+// change alongside.
+func (p *PairSubscription) Update(rate decimal.Decimal) (bool, decimal.Decimal) {
+	if rate.Equal(p.last) {
+		return false, decimal.Zero
+	}
+
+	change := decimal.Zero
+	if !p.last.IsZero() {
+		change = rate.Sub(p.last).Div(p.last).Mul(hundred)
+	}
+
+	if change.Abs().LessThan(p.Threshold) {
+		return false, change
+	}
+
+	p.last = rate
+	return true, change
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+)
+
+func TestParsePair(t *testing.T) {
+	s := newTestRateFetcher(t, "USD,EUR,INR")
+
+	pair, err := s.ParsePair("eur/inr")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if pair != (Pair{From: "EUR", To: "INR"}) || pair.String() != "EUR/INR" {
+		t.Errorf("ParsePair(eur/inr) = %v", pair)
+	}
+
+	tests := []struct {
+		value string
+		code  appErrors.ErrorCode
+	}{
+		{"EURINR", appErrors.ErrInvalidPair},
+		{"EUR/", appErrors.ErrInvalidPair},
+		{"EUR/EUR", appErrors.ErrInvalidPair},
+		{"EUR/XYZ", appErrors.ErrUnsupportedCurrency},
+	}
+
+	for _, tt := range tests {
+		_, err := s.ParsePair(tt.value)
+
+		var customErr *appErrors.CustomError
+		if !errors.As(err, &customErr) || customErr.Code != tt.code {
+			t.Errorf("ParsePair(%q) error = %v, expected %s", tt.value, err, tt.code)
+		}
+	}
+}
+
+func TestPairSubscriptionThreshold(t *testing.T) {
+	pair := Pair{From: "EUR", To: "INR"}
+	subscription, err := NewPairSubscription(pair, "1", decimal.NewFromInt(100))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		rate     string
+		reported bool
+		last     string
+	}{
+		{"100.5", false, "100"},
+		{"100.9", false, "100"},
+		{"101", true, "101"},
+		{"101", false, "101"},
+		{"99.5", true, "99.5"},
+	}
+
+	for _, tt := range tests {
+		reported, change := subscription.Update(decimal.RequireFromString(tt.rate))
+		if reported != tt.reported {
+			t.Errorf("Update(%s) reported %v (change %s%%), expected %v", tt.rate, reported, change, tt.reported)
+		}
+		if !subscription.Last().Equal(decimal.RequireFromString(tt.last)) {
+			t.Errorf("After Update(%s) last = %s, expected %s", tt.rate, subscription.Last(), tt.last)
+		}
+	}
+
+	for _, threshold := range []string{"-1", "half"} {
+		if _, err := NewPairSubscription(pair, threshold, decimal.NewFromInt(1)); err == nil {
+			t.Errorf("Expected error for threshold %q", threshold)
+		}
+	}
+}
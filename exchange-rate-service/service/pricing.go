@@ -0,0 +1,287 @@
+package service
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+
+	"github.com/shopspring/decimal"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+	"github.com/yourusername/exchange-rate-service/money"
+)
+
+type SpreadType string
+
+const (
+	SpreadBasisPoints SpreadType = "bps"
+	SpreadFixed       SpreadType = "fixed"
+)
+
+// PricingRule describes the markup applied on top of the mid-market rate.
+// Empty Client, From or To fields match any value, so a rule with all three
+// empty acts as the default.
+type PricingRule struct {
+	Client     string          `json:"client"`
+	From       string          `json:"from"`
+	To         string          `json:"to"`
+	MinAmount  decimal.Decimal `json:"min_amount"`
+	SpreadType SpreadType      `json:"spread_type"`
+	Spread     decimal.Decimal `json:"spread"`
+	Fee        decimal.Decimal `json:"fee"`
+}
+
+// Quote is a customer-facing price. Fee is charged in the source currency
+// and deducted before conversion at the bid rate.
+type Quote struct {
+	Client    string
+	Amount    money.Money
+	MidRate   decimal.Decimal
+	BidRate   decimal.Decimal
+	AskRate   decimal.Decimal
+	Fee       money.Money
+	NetAmount money.Money
+}
+
+type PricingEngine struct {
+	converter *Converter
+	rules     []PricingRule
+}
+
+func NewPricingEngine(converter *Converter, rules []PricingRule) *PricingEngine {
+	return &PricingEngine{
+		converter: converter,
+		rules:     rules,
+	}
+}
+
+// LoadPricingRules reads a JSON array of pricing rules from path. An empty
+// path yields no rules, which prices everything at mid-market without fees.
+func LoadPricingRules(path string) ([]PricingRule, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []PricingRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, err
+	}
+
+	for _, rule := range rules {
+		if err := rule.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return rules, nil
+}
+
+func (r PricingRule) validate() error {
+	switch r.SpreadType {
+	case "", SpreadBasisPoints, SpreadFixed:
+	default:
+		return appErrors.InvalidPricingError("unknown spread type " + string(r.SpreadType))
+	}
+
+	if r.Spread.IsNegative() {
+		return appErrors.InvalidPricingError("spread must not be negative")
+	}
+	if r.Fee.IsNegative() {
+		return appErrors.InvalidPricingError("fee must not be negative")
+	}
+	if r.MinAmount.IsNegative() {
+		return appErrors.InvalidPricingError("min_amount must not be negative")
+	}
+
+	return nil
+}
+
+func (r PricingRule) matches(client, from, to string, amount decimal.Decimal) bool {
+	if r.Client != "" && r.Client != client {
+		return false
+	}
+	if r.From != "" && r.From != from {
+		return false
+	}
+	if r.To != "" && r.To != to {
+		return false
+	}
+
+	return amount.GreaterThanOrEqual(r.MinAmount)
+}
+
+func (r PricingRule) specificity() int {
+	score := 0
+	if r.Client != "" {
+		score += 4
+	}
+	if r.From != "" {
+		score += 2
+	}
+	if r.To != "" {
+		score += 1
+	}
+
+	return score
+}
+
+// findRule picks the most specific rule matching the request, preferring the
+// highest amount tier among equally specific rules.
+func (p *PricingEngine) findRule(client, from, to string, amount decimal.Decimal) PricingRule {
+	return p.rule(p.ruleIndex(client, from, to, amount))
+}
+
+// ruleIndex returns the index of the rule findRule picks, or -1 when no rule
+// matches.
+func (p *PricingEngine) ruleIndex(client, from, to string, amount decimal.Decimal) int {
+	var candidates []int
+	for i, rule := range p.rules {
+		if rule.matches(client, from, to, amount) {
+			candidates = append(candidates, i)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return -1
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		ri, rj := p.rules[candidates[i]], p.rules[candidates[j]]
+		si, sj := ri.specificity(), rj.specificity()
+		if si != sj {
+			return si > sj
+		}
+		return ri.MinAmount.GreaterThan(rj.MinAmount)
+	})
+
+	return candidates[0]
+}
+
+// rule returns the rule at index, or the zero rule, which prices at
+// mid-market without a fee, for -1.
+func (p *PricingEngine) rule(index int) PricingRule {
+	if index < 0 {
+		return PricingRule{}
+	}
+
+	return p.rules[index]
+}
+
+func (r PricingRule) applySpread(mid decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+	if r.Spread.IsZero() {
+		return mid, mid
+	}
+
+	if r.SpreadType == SpreadFixed {
+		return mid.Sub(r.Spread), mid.Add(r.Spread)
+	}
+
+	markup := mid.Mul(r.Spread).Div(decimal.NewFromInt(10000))
+	return mid.Sub(markup), mid.Add(markup)
+}
+
+func (p *PricingEngine) Quote(client string, amount money.Money, to string, rates map[string]decimal.Decimal) (*Quote, error) {
+	from := amount.Currency()
+	mid, err := p.converter.Rate(from, to, rates)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := p.findRule(client, from, to, amount.Amount())
+
+	bid, ask := rule.applySpread(mid)
+	if !bid.IsPositive() {
+		return nil, appErrors.InvalidPricingError("spread exceeds the mid-market rate")
+	}
+
+	fee := money.New(rule.Fee, from)
+	remaining, err := amount.Sub(fee)
+	if err != nil {
+		return nil, err
+	}
+	if !remaining.IsPositive() {
+		return nil, appErrors.FeeExceedsAmountError()
+	}
+
+	return &Quote{
+		Client:    client,
+		Amount:    amount,
+		MidRate:   mid,
+		BidRate:   bid,
+		AskRate:   ask,
+		Fee:       fee,
+		NetAmount: remaining.Exchange(to, bid).Round(),
+	}, nil
+}
+
+// QuoteReverse solves for the source amount needed to deliver exactly target
+// in the destination currency. The converted part is rounded up to the
+// source currency's minor units before the fee is added, so the delivered
+// amount is never short. NetAmount is what that source amount delivers,
+// as a forward quote would price it, and may exceed target by the rounding.
+func (p *PricingEngine) QuoteReverse(client, from string, target money.Money, rates map[string]decimal.Decimal) (*Quote, error) {
+	to := target.Currency()
+	mid, err := p.converter.Rate(from, to, rates)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tiers are keyed on the amount charged, which itself depends on the
+	// rule's spread and fee. Start from the mid-market estimate and re-pick
+	// the rule from each charged amount until it stops changing.
+	index := p.ruleIndex(client, from, to, target.Amount().Div(mid))
+	tried := make(map[int]bool)
+	var largest *Quote
+	for !tried[index] {
+		tried[index] = true
+
+		quote, err := p.quoteReverse(client, p.rule(index), mid, from, target)
+		if err != nil {
+			return nil, err
+		}
+
+		next := p.ruleIndex(client, from, to, quote.Amount.Amount())
+		if next == index {
+			return quote, nil
+		}
+
+		if largest == nil || quote.Amount.Amount().GreaterThan(largest.Amount.Amount()) {
+			largest = quote
+		}
+		index = next
+	}
+
+	// The rules cycle: each amount falls in the tier of another rule. The
+	// largest amount tried covers target under whichever rule its own tier
+	// picks, so charge that and price it as a forward quote would.
+	return p.Quote(client, largest.Amount, to, rates)
+}
+
+// quoteReverse prices the source amount needed to deliver target with rule.
+func (p *PricingEngine) quoteReverse(client string, rule PricingRule, mid decimal.Decimal, from string, target money.Money) (*Quote, error) {
+	bid, ask := rule.applySpread(mid)
+	if !bid.IsPositive() {
+		return nil, appErrors.InvalidPricingError("spread exceeds the mid-market rate")
+	}
+
+	fee := money.New(rule.Fee, from)
+	converted := money.New(target.Amount().Div(bid), from).RoundUp()
+	amount, err := converted.Add(fee)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Quote{
+		Client:    client,
+		Amount:    amount,
+		MidRate:   mid,
+		BidRate:   bid,
+		AskRate:   ask,
+		Fee:       fee,
+		NetAmount: converted.Exchange(target.Currency(), bid).Round(),
+	}, nil
+}
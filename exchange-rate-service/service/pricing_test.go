@@ -0,0 +1,234 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/exchange-rate-service/money"
+)
+
+func TestQuoteWithoutRules(t *testing.T) {
+	engine := NewPricingEngine(NewConverter(), nil)
+
+	rates := map[string]decimal.Decimal{
+		"USD": decimal.NewFromInt(1),
+		"INR": decimal.NewFromFloat(83.12),
+	}
+
+	quote, err := engine.Quote("", money.New(decimal.NewFromInt(100), "USD"), "INR", rates)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !quote.BidRate.Equal(quote.MidRate) || !quote.AskRate.Equal(quote.MidRate) {
+		t.Errorf("Expected bid and ask to equal mid without rules, got %s/%s/%s", quote.BidRate, quote.MidRate, quote.AskRate)
+	}
+
+	if quote.NetAmount.String() != "8312.00 INR" {
+		t.Errorf("Expected net amount 8312.00 INR, got %s", quote.NetAmount)
+	}
+}
+
+func TestQuoteRuleSelection(t *testing.T) {
+	rules := []PricingRule{
+		{SpreadType: SpreadBasisPoints, Spread: decimal.NewFromInt(100)},
+		{From: "USD", To: "INR", SpreadType: SpreadBasisPoints, Spread: decimal.NewFromInt(50), Fee: decimal.NewFromInt(2)},
+		{From: "USD", To: "INR", MinAmount: decimal.NewFromInt(10000), SpreadType: SpreadBasisPoints, Spread: decimal.NewFromInt(20)},
+		{Client: "acme", SpreadType: SpreadFixed, Spread: decimal.NewFromFloat(0.5)},
+	}
+	engine := NewPricingEngine(NewConverter(), rules)
+
+	rates := map[string]decimal.Decimal{
+		"USD": decimal.NewFromInt(1),
+		"INR": decimal.NewFromInt(80),
+		"EUR": decimal.NewFromFloat(0.5),
+	}
+
+	tests := []struct {
+		name   string
+		client string
+		from   string
+		to     string
+		amount string
+		bid    string
+		ask    string
+		fee    string
+		net    string
+	}{
+		{
+			name:   "Default rule",
+			from:   "USD",
+			to:     "EUR",
+			amount: "100",
+			bid:    "0.495",
+			ask:    "0.505",
+			fee:    "0",
+			net:    "49.50",
+		},
+		{
+			name:   "Pair rule with fee",
+			from:   "USD",
+			to:     "INR",
+			amount: "100",
+			bid:    "79.6",
+			ask:    "80.4",
+			fee:    "2",
+			net:    "7800.80",
+		},
+		{
+			name:   "Amount tier",
+			from:   "USD",
+			to:     "INR",
+			amount: "10000",
+			bid:    "79.84",
+			ask:    "80.16",
+			fee:    "0",
+			net:    "798400.00",
+		},
+		{
+			name:   "Client rule",
+			client: "acme",
+			from:   "USD",
+			to:     "INR",
+			amount: "100",
+			bid:    "79.5",
+			ask:    "80.5",
+			fee:    "0",
+			net:    "7950.00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount := money.New(decimal.RequireFromString(tt.amount), tt.from)
+			quote, err := engine.Quote(tt.client, amount, tt.to, rates)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if !quote.BidRate.Equal(decimal.RequireFromString(tt.bid)) {
+				t.Errorf("Bid = %s, expected %s", quote.BidRate, tt.bid)
+			}
+			if !quote.AskRate.Equal(decimal.RequireFromString(tt.ask)) {
+				t.Errorf("Ask = %s, expected %s", quote.AskRate, tt.ask)
+			}
+			if !quote.Fee.Amount().Equal(decimal.RequireFromString(tt.fee)) {
+				t.Errorf("Fee = %s, expected %s", quote.Fee, tt.fee)
+			}
+			if quote.NetAmount.AmountString() != tt.net {
+				t.Errorf("Net = %s, expected %s", quote.NetAmount.AmountString(), tt.net)
+			}
+		})
+	}
+}
+
+func TestQuoteFeeExceedsAmount(t *testing.T) {
+	rules := []PricingRule{
+		{Fee: decimal.NewFromInt(5)},
+	}
+	engine := NewPricingEngine(NewConverter(), rules)
+
+	rates := map[string]decimal.Decimal{
+		"USD": decimal.NewFromInt(1),
+		"EUR": decimal.NewFromFloat(0.92),
+	}
+
+	_, err := engine.Quote("", money.New(decimal.NewFromInt(5), "USD"), "EUR", rates)
+	if err == nil {
+		t.Error("Expected error when fee covers the whole amount")
+	}
+}
+
+func TestQuoteReverse(t *testing.T) {
+	rules := []PricingRule{
+		{SpreadType: SpreadBasisPoints, Spread: decimal.NewFromInt(50), Fee: decimal.NewFromInt(3)},
+	}
+	engine := NewPricingEngine(NewConverter(), rules)
+
+	rates := map[string]decimal.Decimal{
+		"USD": decimal.NewFromInt(1),
+		"INR": decimal.NewFromFloat(83.12),
+	}
+
+	target := money.New(decimal.NewFromInt(10000), "INR")
+	quote, err := engine.QuoteReverse("", "USD", target, rates)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if short, _ := quote.NetAmount.LessThan(target); short {
+		t.Errorf("Expected net amount of at least %s, got %s", target, quote.NetAmount)
+	}
+
+	// 10000 / 82.7044 = 120.912... rounded up to 120.92, plus the 3 fee.
+	if quote.Amount.String() != "123.92 USD" {
+		t.Errorf("Expected source amount 123.92 USD, got %s", quote.Amount)
+	}
+
+	forward, err := engine.Quote("", quote.Amount, "INR", rates)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if short, _ := forward.NetAmount.LessThan(target); short {
+		t.Errorf("Forward quote delivers %s, short of %s", forward.NetAmount, target)
+	}
+	if !forward.NetAmount.Equal(quote.NetAmount) {
+		t.Errorf("Reverse quote reports %s, forward quote delivers %s", quote.NetAmount, forward.NetAmount)
+	}
+}
+
+func TestQuoteReverseRepicksTier(t *testing.T) {
+	rates := map[string]decimal.Decimal{
+		"USD": decimal.NewFromInt(1),
+		"INR": decimal.NewFromInt(80),
+	}
+	standard := PricingRule{SpreadType: SpreadBasisPoints, Spread: decimal.NewFromInt(50), Fee: decimal.NewFromInt(3)}
+	tier := func(fee int64) PricingRule {
+		return PricingRule{MinAmount: decimal.NewFromInt(1000), SpreadType: SpreadBasisPoints, Spread: decimal.NewFromInt(10), Fee: decimal.NewFromInt(fee)}
+	}
+
+	// The standard bid is 79.6 and the tier bid 79.92.
+	tests := []struct {
+		name   string
+		rules  []PricingRule
+		target int64
+		amount string
+		fee    string
+	}{
+		// 40000 / 79.6 = 502.512... rounded up, plus the 3 fee.
+		{"below the tier", []PricingRule{standard, tier(10)}, 40000, "505.52 USD", "3.00 USD"},
+		// Estimated at 996.25 but charged 1004.26 at the standard rule,
+		// which is in the tier: 79700 / 79.92 = 997.247... plus the 10 fee.
+		{"charged into the tier", []PricingRule{standard, tier(10)}, 79700, "1007.25 USD", "10.00 USD"},
+		// 100000 / 79.92 = 1251.251... plus the 10 fee.
+		{"estimated in the tier", []PricingRule{standard, tier(10)}, 100000, "1261.26 USD", "10.00 USD"},
+		// 1004.26 at the standard rule is in the tier, and 997.25 at the
+		// tier is below it, so the larger amount is charged at the tier.
+		{"rules cycle", []PricingRule{standard, tier(0)}, 79700, "1004.26 USD", "0.00 USD"},
+	}
+
+	for _, tt := range tests {
+		engine := NewPricingEngine(NewConverter(), tt.rules)
+		target := money.New(decimal.NewFromInt(tt.target), "INR")
+
+		quote, err := engine.QuoteReverse("", "USD", target, rates)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+
+		if quote.Amount.String() != tt.amount || quote.Fee.String() != tt.fee {
+			t.Errorf("%s: charged %s with fee %s, expected %s with fee %s", tt.name, quote.Amount, quote.Fee, tt.amount, tt.fee)
+		}
+		if short, _ := quote.NetAmount.LessThan(target); short {
+			t.Errorf("%s: net amount %s is short of %s", tt.name, quote.NetAmount, target)
+		}
+
+		forward, err := engine.Quote("", quote.Amount, "INR", rates)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if !forward.Fee.Equal(quote.Fee) || !forward.NetAmount.Equal(quote.NetAmount) {
+			t.Errorf("%s: reverse quote charges fee %s for %s, forward quote charges %s for %s", tt.name, quote.Fee, quote.NetAmount, forward.Fee, forward.NetAmount)
+		}
+	}
+}
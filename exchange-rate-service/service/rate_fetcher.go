@@ -1,33 +1,97 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/shopspring/decimal"
 	appErrors "github.com/yourusername/exchange-rate-service/errors"
+	"github.com/yourusername/exchange-rate-service/metrics"
+	"github.com/yourusername/exchange-rate-service/money"
+	"golang.org/x/sync/singleflight"
 )
 
-var SupportedCurrencies = map[string]bool{
-	"USD": true,
-	"INR": true,
-	"EUR": true,
-	"JPY": true,
-	"GBP": true,
-	"BTC": true,
-}
-
 type RateFetcherService struct {
-	apiClient *APIClient
-	converter *Converter
-	cache     *Cache
+	apiClient  *APIClient
+	converter  *Converter
+	pricing    *PricingEngine
+	cache      *Cache
+	currencies *CurrencyRegistry
+	staleAfter time.Duration
+	maxRateAge time.Duration
+	scheduler  scheduler
+	watchers   watchers
+
+	// historicalConcurrency bounds upstream calls when filling date ranges.
+	historicalConcurrency int
+
+	latestMatrix atomic.Pointer[CrossRateMatrix]
+
+	// latestLoads collapses concurrent reloads of the latest rates into one
+	// upstream fetch.
+	latestLoads singleflight.Group
 }
 
+const defaultStaleAfter = 2 * time.Hour
+
+// lookbackDays is how far back historical rates can be requested.
+const lookbackDays = 90
+
 func NewRateFetcherService() *RateFetcherService {
+	rules, err := LoadPricingRules(os.Getenv("PRICING_CONFIG"))
+	if err != nil {
+		panic(fmt.Sprintf("failed to load pricing rules: %v", err))
+	}
+
+	currencies, err := LoadCurrencies(os.Getenv("SUPPORTED_CURRENCIES"), os.Getenv("CURRENCY_CONFIG"))
+	if err != nil {
+		panic(fmt.Sprintf("failed to load currencies: %v", err))
+	}
+
+	aliases, err := LoadAliases(os.Getenv("CURRENCY_ALIASES"))
+	if err != nil {
+		panic(fmt.Sprintf("failed to load currency aliases: %v", err))
+	}
+
+	staleAfter := defaultStaleAfter
+	if value := os.Getenv("STALE_AFTER"); value != "" {
+		staleAfter, err = time.ParseDuration(value)
+		if err != nil {
+			panic(fmt.Sprintf("invalid STALE_AFTER: %v", err))
+		}
+	}
+
+	maxRateAge := defaultMaxRateAge
+	if value := os.Getenv("READY_MAX_RATE_AGE"); value != "" {
+		maxRateAge, err = time.ParseDuration(value)
+		if err != nil {
+			panic(fmt.Sprintf("invalid READY_MAX_RATE_AGE: %v", err))
+		}
+	}
+
+	historicalConcurrency := defaultHistoricalConcurrency
+	if value := os.Getenv("HISTORICAL_CONCURRENCY"); value != "" {
+		historicalConcurrency, err = strconv.Atoi(value)
+		if err != nil || historicalConcurrency <= 0 {
+			panic(fmt.Sprintf("invalid HISTORICAL_CONCURRENCY: %s", value))
+		}
+	}
+
+	converter := NewConverter()
 	service := &RateFetcherService{
-		apiClient: NewClient(),
-		converter: NewConverter(),
-		cache:     NewCache(),
+		apiClient:  NewClient(),
+		converter:  converter,
+		pricing:    NewPricingEngine(converter, rules),
+		cache:      NewCache(),
+		currencies: NewCurrencyRegistry(currencies, aliases),
+		staleAfter: staleAfter,
+		maxRateAge: maxRateAge,
+
+		historicalConcurrency: historicalConcurrency,
 	}
 
 	service.loadLatestRates()
@@ -35,63 +99,189 @@ func NewRateFetcherService() *RateFetcherService {
 	return service
 }
 
-func (s *RateFetcherService) loadLatestRates() error {
-	rates, err := s.apiClient.FetchLatestRates()
+// loadLatestRates fetches the latest rates, stores them as a new version
+// and publishes them. Callers arriving while a load is in flight share its
+// result instead of fetching again.
+func (s *RateFetcherService) loadLatestRates() (*RateSnapshot, error) {
+	snapshot, err, _ := s.latestLoads.Do("latest", func() (any, error) {
+		rates, err := s.apiClient.FetchLatestRates()
 
+		if err != nil {
+			return nil, err
+		}
+
+		version, updatedAt := s.cache.SetLatestRates(rates)
+		s.currencies.SetQuoted(rates)
+
+		snapshot := s.latestSnapshot(rates, version, updatedAt)
+		s.latestMatrix.Store(s.buildMatrix(snapshot))
+		s.watchers.publish(snapshot)
+		return snapshot, nil
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	s.cache.SetLatestRates(rates)
-	return nil
+	return snapshot.(*RateSnapshot), nil
+}
 
+// ConvertCurrency converts amount into to at the latest rates, or at the
+// rates for date when it is set, rounding the result with rounding.
+func (s *RateFetcherService) ConvertCurrency(amount money.Money, to string, date *time.Time, rounding money.Rounding) (*Conversion, error) {
+	amount, to = s.normalizeMoney(amount), s.currencies.Normalize(to)
+	err := s.validate(amount.Currency(), to, amount, date)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := s.getRates(date)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.converter.ConvertWithRounding(amount, to, snapshot.Rates, rounding)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.newConversion(amount, result, snapshot)
 }
 
-func (s *RateFetcherService) ConvertCurrency(from, to string, amount string, date *time.Time) (string, error) {
-	err := s.validate(from, to, amount, date)
+// ConvertCurrencyReverse returns the amount of from required to receive
+// target.
+func (s *RateFetcherService) ConvertCurrencyReverse(from string, target money.Money, date *time.Time) (*Conversion, error) {
+	from, target = s.currencies.Normalize(from), s.normalizeMoney(target)
+	err := s.validate(from, target.Currency(), target, date)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	amountDecimal, _ := decimal.NewFromString(amount)
 
-	var rates map[string]decimal.Decimal
-	var err1 error
+	snapshot, err := s.getRates(date)
+	if err != nil {
+		return nil, err
+	}
 
-	if date == nil {
-		rates, err1 = s.getLatestRates()
-	} else {
-		rates, err1 = s.getHistoricalRates(*date)
+	source, err := s.converter.ConvertReverse(from, target, snapshot.Rates)
+	if err != nil {
+		return nil, err
 	}
 
-	if err1 != nil {
-		fmt.Printf("Error in rate-fetcher convert currency %v", err1)
-		return "", err1
+	return s.newConversion(source, target, snapshot)
+}
+
+func (s *RateFetcherService) newConversion(amount, result money.Money, snapshot *RateSnapshot) (*Conversion, error) {
+	rate, err := s.converter.Rate(amount.Currency(), result.Currency(), snapshot.Rates)
+	if err != nil {
+		return nil, err
 	}
 
-	result, err := s.converter.Convert(from, to, amountDecimal, rates)
+	inverse, err := s.converter.Rate(result.Currency(), amount.Currency(), snapshot.Rates)
 	if err != nil {
-		return "", fmt.Errorf("conversion error: %v", err)
+		return nil, err
 	}
 
-	return result, nil
+	return &Conversion{
+		Amount:      amount,
+		Result:      result,
+		Rate:        rate,
+		InverseRate: inverse,
+		Snapshot:    snapshot,
+	}, nil
 }
 
-func (s *RateFetcherService) validate(from, to string, amountStr string, date *time.Time) error {
-	if !SupportedCurrencies[from] {
-		return appErrors.UnsupportedCurrencyError(from)
+// Quote prices a conversion for a client, applying the configured spread and
+// fee on top of the mid-market rate.
+func (s *RateFetcherService) Quote(client string, amount money.Money, to string, date *time.Time) (*Quote, error) {
+	amount, to = s.normalizeMoney(amount), s.currencies.Normalize(to)
+	err := s.validate(amount.Currency(), to, amount, date)
+	if err != nil {
+		return nil, err
 	}
-	if !SupportedCurrencies[to] {
-		return appErrors.UnsupportedCurrencyError(to)
+
+	snapshot, err := s.getRates(date)
+	if err != nil {
+		return nil, err
 	}
 
-	amount, err := decimal.NewFromString(amountStr)
+	return s.pricing.Quote(client, amount, to, snapshot.Rates)
+}
+
+// QuoteReverse prices a conversion that delivers exactly target.
+func (s *RateFetcherService) QuoteReverse(client, from string, target money.Money, date *time.Time) (*Quote, error) {
+	from, target = s.currencies.Normalize(from), s.normalizeMoney(target)
+	err := s.validate(from, target.Currency(), target, date)
 	if err != nil {
-		return appErrors.InvalidAmountError()
+		return nil, err
+	}
+
+	snapshot, err := s.getRates(date)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.pricing.QuoteReverse(client, from, target, snapshot.Rates)
+}
+
+// normalizeMoney re-labels amount with the canonical form of its currency
+// code, resolving case and aliases.
+func (s *RateFetcherService) normalizeMoney(amount money.Money) money.Money {
+	return money.New(amount.Amount(), s.currencies.Normalize(amount.Currency()))
+}
+
+// getRates returns the rate snapshot for date, or the latest one when date
+// is nil, with legacy currencies priced from their successors.
+func (s *RateFetcherService) getRates(date *time.Time) (*RateSnapshot, error) {
+	if date == nil {
+		return s.getLatestRates()
+	}
+
+	rates, err := s.getHistoricalRates(*date)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RateSnapshot{
+		Rates:     applyRedenominations(rates, *date),
+		Date:      date,
+		UpdatedAt: s.cache.GetHistoricalFetchedAt(*date),
+		Provider:  s.apiClient.Name(),
+	}, nil
+}
+
+// latestSnapshot labels rates with the version and time the cache stored
+// them under.
+func (s *RateFetcherService) latestSnapshot(rates map[string]decimal.Decimal, version uint64, updatedAt time.Time) *RateSnapshot {
+	return &RateSnapshot{
+		Rates:     applyRedenominations(rates, time.Now().UTC()),
+		UpdatedAt: updatedAt,
+		Provider:  s.apiClient.Name(),
+		Stale:     s.isStale(updatedAt),
+		Version:   version,
+	}
+}
+
+func (s *RateFetcherService) isStale(updatedAt time.Time) bool {
+	return time.Since(updatedAt) > s.staleAfter
+}
+
+func (s *RateFetcherService) validate(from, to string, amount money.Money, date *time.Time) error {
+	if !s.currencies.IsSupported(from) {
+		return appErrors.UnsupportedCurrencyError(from)
 	}
-	if amount.LessThanOrEqual(decimal.Zero) {
+	if !s.currencies.IsSupported(to) {
+		return appErrors.UnsupportedCurrencyError(to)
+	}
+
+	if !amount.IsPositive() {
 		return appErrors.InvalidAmountError()
 	}
 
+	return validateDate(date)
+}
+
+// validateDate checks that date, when set, is neither in the future nor
+// beyond the lookback window.
+func validateDate(date *time.Time) error {
 	if date != nil {
 
 		now := time.Now().UTC()
@@ -101,7 +291,7 @@ func (s *RateFetcherService) validate(from, to string, amountStr string, date *t
 			return appErrors.FutureDateError()
 		}
 
-		cutoffDate := now.AddDate(0, 0, -90).Truncate(24 * time.Hour)
+		cutoffDate := now.AddDate(0, 0, -lookbackDays).Truncate(24 * time.Hour)
 		if dateUTC.Truncate(24 * time.Hour).Before(cutoffDate) {
 			return appErrors.DateTooOldError()
 		}
@@ -110,18 +300,48 @@ func (s *RateFetcherService) validate(from, to string, amountStr string, date *t
 	return nil
 }
 
-func (s *RateFetcherService) getLatestRates() (map[string]decimal.Decimal, error) {
+// UncachedDays returns how many days from start to end, inclusive, would
+// have their rates fetched from the provider: days within the lookback
+// window whose rates are not cached.
+func (s *RateFetcherService) UncachedDays(start, end time.Time) int {
+	day := 24 * time.Hour
+	start, end = start.UTC().Truncate(day), end.UTC().Truncate(day)
+	if cutoff := time.Now().UTC().AddDate(0, 0, -lookbackDays).Truncate(day); start.Before(cutoff) {
+		start = cutoff
+	}
 
-	rates, found := s.cache.GetLatestRates()
+	uncached := 0
+	for date := start; !date.After(end); date = date.Add(day) {
+		if validateDate(&date) != nil {
+			break
+		}
+		if !s.cache.HasHistoricalRates(date) {
+			uncached++
+		}
+	}
+
+	return uncached
+}
+
+func (s *RateFetcherService) getLatestRates() (*RateSnapshot, error) {
+
+	rates, version, updatedAt, found := s.cache.GetLatestVersion()
+	metrics.ObserveCacheLookup("latest", found)
 	if found {
-		return rates, nil
+		return s.latestSnapshot(rates, version, updatedAt), nil
 	}
 
-	return s.apiClient.FetchLatestRates()
+	snapshot, err := s.loadLatestRates()
+	if err != nil {
+		return nil, unavailable(err)
+	}
+
+	return snapshot, nil
 }
 
 func (s *RateFetcherService) getHistoricalRates(date time.Time) (map[string]decimal.Decimal, error) {
 	rates, found := s.cache.GetHistoricalRates(date)
+	metrics.ObserveCacheLookup("historical", found)
 
 	if found {
 		return rates, nil
@@ -130,19 +350,33 @@ func (s *RateFetcherService) getHistoricalRates(date time.Time) (map[string]deci
 	ratescache, err := s.apiClient.FetchHistoricalRates(date)
 
 	if err != nil {
-		return nil, err
+		return nil, unavailable(err)
 	}
 
 	s.cache.SetHistoricalRates(date, ratescache)
 	return ratescache, nil
 }
 
+// unavailable reports a provider that could not be reached on a cache miss
+// as RATES_UNAVAILABLE, keeping the failure as its cause. Timeouts, bad
+// statuses and bad responses are reported as they are.
+func unavailable(err error) error {
+	if !errors.Is(err, &appErrors.CustomError{Code: appErrors.ErrAPIFetchFailed}) {
+		return err
+	}
+
+	return appErrors.RatesUnavailableError(err)
+}
+
 func (s *RateFetcherService) StartHourlyRefresh() {
-	ticker := time.NewTicker(1 * time.Hour)
+	ticker := time.NewTicker(refreshInterval)
+	s.scheduler.start(refreshInterval)
 
 	go func() {
 		for range ticker.C {
-			if err := s.loadLatestRates(); err != nil {
+			_, err := s.loadLatestRates()
+			s.scheduler.ran(err)
+			if err != nil {
 				fmt.Printf("Error refreshing rates: %v/n", err)
 			} else {
 				fmt.Println("Latest rates refreshed")
@@ -156,6 +390,21 @@ func (s *RateFetcherService) StartHourlyRefresh() {
 
 }
 
+// Currencies lists the currencies available for conversion.
+func (s *RateFetcherService) Currencies() []Currency {
+	return s.currencies.List()
+}
+
+// LatestRatesAge returns how long ago the latest rates were fetched.
+func (s *RateFetcherService) LatestRatesAge() time.Duration {
+	return time.Since(s.cache.GetLastUpdated())
+}
+
+// HistoricalDates returns the number of dates with cached historical rates.
+func (s *RateFetcherService) HistoricalDates() int {
+	return s.cache.HistoricalCount()
+}
+
 func (s *RateFetcherService) GetCacheStats() map[string]interface{} {
 	lastUpdated := s.cache.GetLastUpdated()
 
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestConcurrentLatestLoadsShareOneFetch(t *testing.T) {
+	var fetches atomic.Int32
+	arrived := make(chan struct{}, 1)
+	release := make(chan struct{})
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		select {
+		case arrived <- struct{}{}:
+		default:
+		}
+		<-release
+		w.Write([]byte(`{"success": true, "quotes": {"USDEUR": 0.8}}`))
+	}))
+	defer upstream.Close()
+
+	s := newTestRateFetcher(t, "USD,EUR")
+	s.apiClient = &APIClient{baseURL: upstream.URL, client: &http.Client{Timeout: time.Second}}
+
+	const callers = 10
+	snapshots := make([]*RateSnapshot, callers)
+	var wg sync.WaitGroup
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			snapshot, err := s.getRates(nil)
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+			snapshots[i] = snapshot
+		}()
+	}
+
+	<-arrived
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("Expected one upstream fetch, got %d", n)
+	}
+
+	_, _, updatedAt, _ := s.cache.GetLatestVersion()
+	for i, snapshot := range snapshots {
+		if snapshot == nil {
+			continue
+		}
+		if snapshot.Version != 1 || !snapshot.UpdatedAt.Equal(updatedAt) {
+			t.Errorf("Caller %d got version %d updated at %s, expected version 1 updated at %s", i, snapshot.Version, snapshot.UpdatedAt, updatedAt)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const redisRateLimitPrefix = "ratelimit:"
+
+// takeScript refills and takes from a bucket stored as a hash of tokens
+// and updated, in seconds, atomically. The bucket expires once it would be
+// full again. Tokens are returned as a string, as Redis truncates Lua
+// numbers to integers.
+var takeScript = redis.NewScript(`
+local burst = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local n = tonumber(ARGV[3])
+local now = tonumber(ARGV[4])
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "updated")
+local tokens = tonumber(state[1])
+local updated = tonumber(state[2])
+if tokens == nil or updated == nil then
+	tokens = burst
+	updated = now
+end
+
+if now > updated then
+	tokens = math.min(burst, tokens + (now - updated) * rate)
+	updated = now
+end
+
+local allowed = 0
+if tokens >= n then
+	tokens = tokens - n
+	allowed = 1
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "updated", tostring(updated))
+redis.call("PEXPIRE", KEYS[1], math.ceil((burst - tokens) / rate * 1000) + 1000)
+
+return {allowed, tostring(tokens)}
+`)
+
+// RedisRateLimitStore keeps buckets in Redis, so replicas share them.
+type RedisRateLimitStore struct {
+	client redis.Scripter
+}
+
+func NewRedisRateLimitStore(client redis.Scripter) *RedisRateLimitStore {
+	return &RedisRateLimitStore{
+		client: client,
+	}
+}
+
+func (s *RedisRateLimitStore) Take(ctx context.Context, key string, limit RateLimit, n int, now time.Time) (RateLimitResult, error) {
+	seconds := float64(now.UnixMicro()) / 1e6
+
+	reply, err := takeScript.Run(ctx, s.client, []string{redisRateLimitPrefix + key},
+		limit.Burst, limit.rate(), n, strconv.FormatFloat(seconds, 'f', 6, 64)).Slice()
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+
+	allowed, _ := reply[0].(int64)
+	tokensText, _ := reply[1].(string)
+	tokens, err := strconv.ParseFloat(tokensText, 64)
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+
+	return newRateLimitResult(limit, tokens, n, allowed == 1), nil
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+)
+
+var (
+	defaultCachedRateLimit   = RateLimit{Burst: 600, Period: time.Minute}
+	defaultUpstreamRateLimit = RateLimit{Burst: 100, Period: time.Hour}
+	defaultAuthFailureLimit  = RateLimit{Burst: 10, Period: time.Minute}
+)
+
+const rateLimitSweepInterval = time.Minute
+
+// RateLimit is a token bucket holding up to Burst tokens, refilled at
+// Burst tokens per Period. The zero RateLimit is unlimited.
+type RateLimit struct {
+	Burst  int
+	Period time.Duration
+}
+
+// ParseRateLimit reads a limit written as "<burst>/<period>", such as
+// "600/1m". "off" is unlimited.
+func ParseRateLimit(value string) (RateLimit, error) {
+	if value == "off" {
+		return RateLimit{}, nil
+	}
+
+	burst, period, ok := strings.Cut(value, "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q, expected <burst>/<period>", value)
+	}
+
+	limit := RateLimit{}
+	var err error
+	if limit.Burst, err = strconv.Atoi(burst); err != nil || limit.Burst <= 0 {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q, burst must be a positive integer", value)
+	}
+	if limit.Period, err = time.ParseDuration(period); err != nil || limit.Period <= 0 {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q, period must be a positive duration", value)
+	}
+
+	return limit, nil
+}
+
+func (l RateLimit) Unlimited() bool {
+	return l.Burst == 0
+}
+
+// rate returns the refill rate in tokens per second.
+func (l RateLimit) rate() float64 {
+	return float64(l.Burst) / l.Period.Seconds()
+}
+
+// String formats l as a RateLimit-Policy item, e.g. "600;w=60".
+func (l RateLimit) String() string {
+	return fmt.Sprintf("%d;w=%d", l.Burst, int(math.Ceil(l.Period.Seconds())))
+}
+
+// RateLimitResult is the state of a bucket after a request took tokens
+// from it.
+type RateLimitResult struct {
+	Bucket    string
+	Allowed   bool
+	Limit     RateLimit
+	Remaining int
+	// Reset is how long until the bucket is full again, and RetryAfter how
+	// long until a denied request would be allowed.
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+func newRateLimitResult(limit RateLimit, tokens float64, n int, allowed bool) RateLimitResult {
+	result := RateLimitResult{
+		Allowed:   allowed,
+		Limit:     limit,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     secondsDuration((float64(limit.Burst) - tokens) / limit.rate()),
+	}
+
+	if !allowed {
+		result.RetryAfter = secondsDuration((float64(n) - tokens) / limit.rate())
+	}
+
+	return result
+}
+
+func secondsDuration(seconds float64) time.Duration {
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
+
+// RateLimitStore keeps token buckets by key. Take removes n tokens from the
+// bucket if it holds that many, and otherwise leaves it unchanged.
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, limit RateLimit, n int, now time.Time) (RateLimitResult, error)
+}
+
+// RateLimiter limits each client with two token buckets: one for requests
+// answered from cached rates, and one, usually much smaller, for requests
+// that fetch rates from the provider. A third bucket limits the failed
+// authentication attempts from each IP address.
+type RateLimiter struct {
+	store        RateLimitStore
+	cached       RateLimit
+	upstream     RateLimit
+	authFailures RateLimit
+	now          func() time.Time
+}
+
+// NewRateLimiter reads the limits from RATE_LIMIT_CACHED,
+// RATE_LIMIT_UPSTREAM and RATE_LIMIT_AUTH_FAILURES. Buckets are shared
+// through the Redis server at RATE_LIMIT_REDIS_URL, or kept in memory when
+// it is not set.
+func NewRateLimiter() *RateLimiter {
+	cached := rateLimitFromEnv("RATE_LIMIT_CACHED", defaultCachedRateLimit)
+	upstream := rateLimitFromEnv("RATE_LIMIT_UPSTREAM", defaultUpstreamRateLimit)
+	authFailures := rateLimitFromEnv("RATE_LIMIT_AUTH_FAILURES", defaultAuthFailureLimit)
+
+	var store RateLimitStore = NewMemoryRateLimitStore()
+	if url := os.Getenv("RATE_LIMIT_REDIS_URL"); url != "" {
+		options, err := redis.ParseURL(url)
+		if err != nil {
+			panic(fmt.Sprintf("invalid RATE_LIMIT_REDIS_URL: %v", err))
+		}
+		store = NewRedisRateLimitStore(redis.NewClient(options))
+	}
+
+	limiter := newRateLimiter(store, cached, upstream)
+	limiter.authFailures = authFailures
+	return limiter
+}
+
+func newRateLimiter(store RateLimitStore, cached, upstream RateLimit) *RateLimiter {
+	return &RateLimiter{
+		store:    store,
+		cached:   cached,
+		upstream: upstream,
+		now:      time.Now,
+	}
+}
+
+func rateLimitFromEnv(name string, fallback RateLimit) RateLimit {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+
+	limit, err := ParseRateLimit(value)
+	if err != nil {
+		panic(fmt.Sprintf("invalid %s: %v", name, err))
+	}
+
+	return limit
+}
+
+// Allow charges a request from client one token from the cached bucket,
+// or, when it will make fetches calls to the provider, that many tokens
+// from the upstream bucket. A request needing more tokens than the bucket
+// holds could never be allowed and returns TOO_MANY_FETCHES; other errors
+// are store failures. The result is nil when the bucket is unlimited.
+func (l *RateLimiter) Allow(ctx context.Context, client string, fetches int) (*RateLimitResult, error) {
+	bucket, limit, n := "cached", l.cached, 1
+	if fetches > 0 {
+		bucket, limit, n = "upstream", l.upstream, fetches
+	}
+
+	if limit.Unlimited() {
+		return nil, nil
+	}
+
+	if n > limit.Burst {
+		return nil, appErrors.TooManyFetchesError(n, limit.Burst)
+	}
+
+	result, err := l.store.Take(ctx, client+":"+bucket, limit, n, l.now())
+	if err != nil {
+		return nil, err
+	}
+
+	result.Bucket = bucket
+	return &result, nil
+}
+
+// AllowAuthAttempt checks whether client, an IP address, may try to
+// authenticate: whether it has failed fewer times than its auth bucket
+// holds. It takes no token; AuthFailed charges each failed attempt. The
+// result is nil when the bucket is unlimited.
+func (l *RateLimiter) AllowAuthAttempt(ctx context.Context, client string) (*RateLimitResult, error) {
+	limit := l.authFailures
+	if limit.Unlimited() {
+		return nil, nil
+	}
+
+	result, err := l.store.Take(ctx, client+":auth", limit, 0, l.now())
+	if err != nil {
+		return nil, err
+	}
+
+	result.Bucket = "auth"
+	if result.Remaining < 1 {
+		// The bucket holds one token once it is Burst-1 tokens short of full.
+		result.Allowed = false
+		result.RetryAfter = result.Reset - secondsDuration(float64(limit.Burst-1)/limit.rate())
+	}
+
+	return &result, nil
+}
+
+// AuthFailed charges a failed authentication attempt from client to its
+// auth bucket.
+func (l *RateLimiter) AuthFailed(ctx context.Context, client string) error {
+	if l.authFailures.Unlimited() {
+		return nil
+	}
+
+	_, err := l.store.Take(ctx, client+":auth", l.authFailures, 1, l.now())
+	return err
+}
+
+// MemoryRateLimitStore keeps buckets in memory, for a single replica.
+type MemoryRateLimitStore struct {
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	mu        sync.Mutex
+}
+
+// tokenBucket is a bucket's tokens at updated, and when it will be full
+// again.
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+func (s *MemoryRateLimitStore) Take(ctx context.Context, key string, limit RateLimit, n int, now time.Time) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(limit.Burst), updated: now}
+		s.buckets[key] = bucket
+	}
+
+	bucket.tokens = refill(bucket.tokens, limit, now.Sub(bucket.updated))
+	bucket.updated = now
+
+	allowed := bucket.tokens >= float64(n)
+	if allowed {
+		bucket.tokens -= float64(n)
+	}
+
+	result := newRateLimitResult(limit, bucket.tokens, n, allowed)
+	bucket.full = now.Add(result.Reset)
+
+	return result, nil
+}
+
+// sweep drops buckets that have refilled since their last use, at most
+// once a minute, as a full bucket is the same as none. The caller holds
+// s.mu.
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, bucket := range s.buckets {
+		if !now.Before(bucket.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// refill adds the tokens accrued over elapsed to tokens, up to the burst.
+func refill(tokens float64, limit RateLimit, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return tokens
+	}
+
+	return math.Min(float64(limit.Burst), tokens+elapsed.Seconds()*limit.rate())
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		value   string
+		limit   RateLimit
+		wantErr bool
+	}{
+		{"600/1m", RateLimit{Burst: 600, Period: time.Minute}, false},
+		{"60/1h", RateLimit{Burst: 60, Period: time.Hour}, false},
+		{"off", RateLimit{}, false},
+		{"600", RateLimit{}, true},
+		{"0/1m", RateLimit{}, true},
+		{"10/soon", RateLimit{}, true},
+	}
+
+	for _, tt := range tests {
+		limit, err := ParseRateLimit(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseRateLimit(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if limit != tt.limit {
+			t.Errorf("ParseRateLimit(%q) = %+v, expected %+v", tt.value, limit, tt.limit)
+		}
+	}
+
+	if policy := (RateLimit{Burst: 600, Period: time.Minute}).String(); policy != "600;w=60" {
+		t.Errorf("Policy = %q, expected 600;w=60", policy)
+	}
+}
+
+// testRateLimitStore takes tokens from a 2/1s bucket and checks that it
+// empties, refills over time, and keeps other keys apart.
+func testRateLimitStore(t *testing.T, store RateLimitStore, advance func(time.Duration)) {
+	t.Helper()
+
+	ctx := context.Background()
+	limit := RateLimit{Burst: 2, Period: time.Second}
+	now := time.Date(2025, 11, 28, 12, 0, 0, 0, time.UTC)
+
+	take := func(key string, n int) RateLimitResult {
+		t.Helper()
+		result, err := store.Take(ctx, key, limit, n, now)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return result
+	}
+
+	if result := take("a", 1); !result.Allowed || result.Remaining != 1 || result.Reset != 500*time.Millisecond {
+		t.Errorf("First take = %+v, expected 1 remaining resetting in 500ms", result)
+	}
+	if result := take("a", 1); !result.Allowed || result.Remaining != 0 {
+		t.Errorf("Second take = %+v, expected 0 remaining", result)
+	}
+
+	result := take("a", 1)
+	if result.Allowed || result.RetryAfter != 500*time.Millisecond || result.Reset != time.Second {
+		t.Errorf("Third take = %+v, expected denial retrying in 500ms", result)
+	}
+
+	if result := take("b", 2); !result.Allowed {
+		t.Errorf("Expected another key to have its own bucket, got %+v", result)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	advance(500 * time.Millisecond)
+	if result := take("a", 1); !result.Allowed || result.Remaining != 0 {
+		t.Errorf("Take after refill = %+v, expected it allowed", result)
+	}
+	if result := take("a", 2); result.Allowed || result.RetryAfter != time.Second {
+		t.Errorf("Take of 2 = %+v, expected denial retrying in 1s", result)
+	}
+}
+
+func TestMemoryRateLimitStore(t *testing.T) {
+	testRateLimitStore(t, NewMemoryRateLimitStore(), func(time.Duration) {})
+}
+
+func TestMemoryRateLimitStoreSweepsFullBuckets(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	limit := RateLimit{Burst: 10, Period: time.Hour}
+	now := time.Date(2025, 11, 28, 12, 0, 0, 0, time.UTC)
+
+	store.Take(context.Background(), "short", RateLimit{Burst: 1, Period: time.Second}, 1, now)
+	store.Take(context.Background(), "long", limit, 5, now)
+
+	store.Take(context.Background(), "other", limit, 1, now.Add(2*time.Minute))
+	if _, ok := store.buckets["short"]; ok {
+		t.Error("Expected the refilled bucket to be swept")
+	}
+	if _, ok := store.buckets["long"]; !ok {
+		t.Error("Expected the bucket still refilling to be kept")
+	}
+}
+
+func TestRedisRateLimitStore(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	testRateLimitStore(t, NewRedisRateLimitStore(client), server.FastForward)
+
+	if ttl := server.TTL(redisRateLimitPrefix + "a"); ttl <= 0 {
+		t.Errorf("Expected the bucket to expire, TTL %v", ttl)
+	}
+}
+
+func TestRateLimiterAllow(t *testing.T) {
+	l := newRateLimiter(NewMemoryRateLimitStore(),
+		RateLimit{Burst: 5, Period: time.Minute},
+		RateLimit{Burst: 3, Period: time.Hour})
+
+	result, err := l.Allow(context.Background(), "ip:1.2.3.4", 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Bucket != "cached" || result.Remaining != 4 {
+		t.Errorf("Cached request = %+v, expected cached bucket with 4 remaining", result)
+	}
+
+	_, err = l.Allow(context.Background(), "ip:1.2.3.4", 10)
+	expectCode(t, err, appErrors.ErrTooManyFetches)
+
+	result, _ = l.Allow(context.Background(), "ip:1.2.3.4", 3)
+	if result.Bucket != "upstream" || !result.Allowed || result.Remaining != 0 {
+		t.Errorf("Upstream request = %+v, expected a full upstream bucket charged", result)
+	}
+
+	result, _ = l.Allow(context.Background(), "ip:1.2.3.4", 1)
+	if result.Allowed {
+		t.Errorf("Expected the empty upstream bucket to deny, got %+v", result)
+	}
+
+	if result, _ := l.Allow(context.Background(), "ip:1.2.3.4", 0); !result.Allowed {
+		t.Errorf("Expected cached requests to be unaffected, got %+v", result)
+	}
+
+	l.cached = RateLimit{}
+	if result, _ := l.Allow(context.Background(), "ip:1.2.3.4", 0); result != nil {
+		t.Errorf("Expected no result for an unlimited bucket, got %+v", result)
+	}
+}
+
+func TestDefaultUpstreamLimitCoversLookback(t *testing.T) {
+	// A time series over the whole lookback fetches every day in it, today
+	// included, at once.
+	if days := lookbackDays + 1; defaultUpstreamRateLimit.Burst < days {
+		t.Errorf("Default upstream burst %d cannot fetch the %d days of the lookback", defaultUpstreamRateLimit.Burst, days)
+	}
+}
+
+func TestRateLimiterAuthFailures(t *testing.T) {
+	now := time.Date(2025, 11, 28, 10, 0, 0, 0, time.UTC)
+	l := newRateLimiter(NewMemoryRateLimitStore(), RateLimit{}, RateLimit{})
+	l.authFailures = RateLimit{Burst: 2, Period: time.Minute}
+	l.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if result, err := l.AllowAuthAttempt(ctx, "ip:1.2.3.4"); err != nil || !result.Allowed {
+			t.Fatalf("Attempt %d = %+v, %v, expected it to be allowed", i, result, err)
+		}
+		if err := l.AuthFailed(ctx, "ip:1.2.3.4"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	result, _ := l.AllowAuthAttempt(ctx, "ip:1.2.3.4")
+	if result.Allowed || result.Bucket != "auth" || result.RetryAfter != 30*time.Second {
+		t.Errorf("Attempt after two failures = %+v, expected a denial retrying after 30s", result)
+	}
+	if result, _ := l.AllowAuthAttempt(ctx, "ip:5.6.7.8"); !result.Allowed {
+		t.Errorf("Expected other addresses to be unaffected, got %+v", result)
+	}
+
+	now = now.Add(30 * time.Second)
+	if result, _ := l.AllowAuthAttempt(ctx, "ip:1.2.3.4"); !result.Allowed {
+		t.Errorf("Expected an attempt once a token refilled, got %+v", result)
+	}
+}
+
+func TestUncachedDays(t *testing.T) {
+	s := newTestRateFetcher(t, "USD,EUR")
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	rates := map[string]decimal.Decimal{"USD": decimal.NewFromInt(1)}
+	s.cache.SetHistoricalRates(today.AddDate(0, 0, -2), rates)
+
+	if days := s.UncachedDays(today.AddDate(0, 0, -3), today.AddDate(0, 0, -1)); days != 2 {
+		t.Errorf("UncachedDays = %d, expected 2", days)
+	}
+	if days := s.UncachedDays(today.AddDate(-1, 0, 0), today.AddDate(0, 0, -lookbackDays)); days != 1 {
+		t.Errorf("UncachedDays before the lookback = %d, expected 1", days)
+	}
+	if days := s.UncachedDays(today.AddDate(0, 0, 1), today.AddDate(0, 0, 5)); days != 0 {
+		t.Errorf("UncachedDays in the future = %d, expected 0", days)
+	}
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+)
+
+// RateTable holds the rates of each symbol per unit of Base.
+type RateTable struct {
+	Base     string
+	Rates    map[string]decimal.Decimal
+	Snapshot *RateSnapshot
+}
+
+// GetRates re-bases the latest rate table, or the one for date when set, to
+// base and keeps only symbols. With no symbols every supported currency
+// other than base is returned.
+func (s *RateFetcherService) GetRates(base string, symbols []string, date *time.Time) (*RateTable, error) {
+	base, symbols, err := s.resolveSymbols(base, symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateDate(date); err != nil {
+		return nil, err
+	}
+
+	snapshot, err := s.getRates(date)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.rebase(base, symbols, snapshot)
+}
+
+// RebaseSnapshot re-bases snapshot, such as one received from WatchRates,
+// to base and keeps only symbols, with the same defaults as GetRates.
+func (s *RateFetcherService) RebaseSnapshot(base string, symbols []string, snapshot *RateSnapshot) (*RateTable, error) {
+	base, symbols, err := s.resolveSymbols(base, symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.rebase(base, symbols, snapshot)
+}
+
+// resolveSymbols normalizes base and symbols and checks they are supported.
+// An empty symbol list expands to all supported currencies except base.
+func (s *RateFetcherService) resolveSymbols(base string, symbols []string) (string, []string, error) {
+	base = s.currencies.Normalize(base)
+	if !s.currencies.IsSupported(base) {
+		return "", nil, appErrors.UnsupportedCurrencyError(base)
+	}
+
+	if len(symbols) == 0 {
+		for _, currency := range s.currencies.List() {
+			if currency.Code != base {
+				symbols = append(symbols, currency.Code)
+			}
+		}
+		return base, symbols, nil
+	}
+
+	resolved := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		symbol = s.currencies.Normalize(symbol)
+		if !s.currencies.IsSupported(symbol) {
+			return "", nil, appErrors.UnsupportedCurrencyError(symbol)
+		}
+		resolved = append(resolved, symbol)
+	}
+
+	return base, resolved, nil
+}
+
+func (s *RateFetcherService) rebase(base string, symbols []string, snapshot *RateSnapshot) (*RateTable, error) {
+	rates := make(map[string]decimal.Decimal, len(symbols))
+	for _, symbol := range symbols {
+		rate, err := s.converter.Rate(base, symbol, snapshot.Rates)
+		if err != nil {
+			return nil, err
+		}
+		rates[symbol] = rate
+	}
+
+	return &RateTable{
+		Base:     base,
+		Rates:    rates,
+		Snapshot: snapshot,
+	}, nil
+}
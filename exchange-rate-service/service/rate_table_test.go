@@ -0,0 +1,70 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func newTestRateFetcher(t *testing.T, codes string) *RateFetcherService {
+	t.Helper()
+
+	currencies, err := LoadCurrencies(codes, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	return &RateFetcherService{
+		converter:  NewConverter(),
+		cache:      NewCache(),
+		currencies: NewCurrencyRegistry(currencies, nil),
+	}
+}
+
+func TestRebase(t *testing.T) {
+	s := newTestRateFetcher(t, "USD,EUR,INR")
+	snapshot := &RateSnapshot{
+		Rates: map[string]decimal.Decimal{
+			"USD": decimal.NewFromInt(1),
+			"EUR": decimal.RequireFromString("0.8"),
+			"INR": decimal.NewFromInt(80),
+		},
+	}
+
+	base, symbols, err := s.resolveSymbols("eur", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if base != "EUR" || len(symbols) != 2 {
+		t.Fatalf("Expected EUR base with 2 symbols, got %s %v", base, symbols)
+	}
+
+	table, err := s.rebase(base, symbols, snapshot)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"USD": "1.25",
+		"INR": "100",
+	}
+	for symbol, rate := range expected {
+		if !table.Rates[symbol].Equal(decimal.RequireFromString(rate)) {
+			t.Errorf("%s rate = %s, expected %s", symbol, table.Rates[symbol], rate)
+		}
+	}
+	if _, ok := table.Rates["EUR"]; ok {
+		t.Error("Base currency should not be listed")
+	}
+}
+
+func TestResolveSymbolsUnsupported(t *testing.T) {
+	s := newTestRateFetcher(t, "USD,EUR")
+
+	if _, _, err := s.resolveSymbols("USD", []string{"EUR", "XYZ"}); err == nil {
+		t.Error("Expected error for unsupported symbol")
+	}
+	if _, _, err := s.resolveSymbols("XYZ", nil); err == nil {
+		t.Error("Expected error for unsupported base")
+	}
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	appErrors "github.com/yourusername/exchange-rate-service/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+const defaultHistoricalConcurrency = 4
+
+// Timeseries holds one re-based rate table per day, keyed by YYYY-MM-DD.
+type Timeseries struct {
+	Base      string
+	StartDate time.Time
+	EndDate   time.Time
+	Rates     map[string]map[string]decimal.Decimal
+	Provider  string
+
+	// Snapshots are the historical snapshots the rates came from.
+	Snapshots []*RateSnapshot
+}
+
+// GetTimeseries returns daily rates for symbols against base for every date
+// from start to end inclusive. Dates missing from the cache are fetched with
+// at most historicalConcurrency upstream calls in flight.
+func (s *RateFetcherService) GetTimeseries(base string, symbols []string, start, end time.Time) (*Timeseries, error) {
+	base, symbols, err := s.resolveSymbols(base, symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateDateRange(start, end); err != nil {
+		return nil, err
+	}
+
+	var dates []time.Time
+	for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+		dates = append(dates, date)
+	}
+
+	tables, err := s.getRateTables(base, symbols, dates)
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make(map[string]map[string]decimal.Decimal, len(tables))
+	for i, table := range tables {
+		rates[dates[i].Format("2006-01-02")] = table.Rates
+	}
+
+	return &Timeseries{
+		Base:      base,
+		StartDate: start,
+		EndDate:   end,
+		Rates:     rates,
+		Provider:  s.apiClient.Name(),
+		Snapshots: tableSnapshots(tables),
+	}, nil
+}
+
+func validateDateRange(start, end time.Time) error {
+	if start.After(end) {
+		return appErrors.InvalidDateRangeError()
+	}
+
+	if err := validateDate(&start); err != nil {
+		return err
+	}
+
+	return validateDate(&end)
+}
+
+// getRateTables loads the re-based table for each date concurrently and
+// returns them in the order of dates. The first error aborts the result,
+// and dates not started by then are skipped.
+func (s *RateFetcherService) getRateTables(base string, symbols []string, dates []time.Time) ([]*RateTable, error) {
+	tables := make([]*RateTable, len(dates))
+
+	g, ctx := errgroup.WithContext(context.Background())
+	g.SetLimit(s.concurrencyLimit())
+	for i := range dates {
+		g.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			snapshot, err := s.getRates(&dates[i])
+			if err != nil {
+				return err
+			}
+
+			tables[i], err = s.rebase(base, symbols, snapshot)
+			return err
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return tables, nil
+}
+
+// forEachBounded calls fn for every index below n, running at most
+// historicalConcurrency calls at once, and waits for all of them.
+func (s *RateFetcherService) forEachBounded(n int, fn func(i int)) {
+	semaphore := make(chan struct{}, s.concurrencyLimit())
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// concurrencyLimit returns how many historical dates may be loaded at once.
+func (s *RateFetcherService) concurrencyLimit() int {
+	if s.historicalConcurrency <= 0 {
+		return defaultHistoricalConcurrency
+	}
+
+	return s.historicalConcurrency
+}
+
+func tableSnapshots(tables []*RateTable) []*RateSnapshot {
+	snapshots := make([]*RateSnapshot, len(tables))
+	for i, table := range tables {
+		snapshots[i] = table.Snapshot
+	}
+
+	return snapshots
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestGetTimeseries(t *testing.T) {
+	s := newTestRateFetcher(t, "USD,EUR,INR")
+
+	end := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	start := end.AddDate(0, 0, -2)
+	for i, date := 0, start; !date.After(end); i, date = i+1, date.AddDate(0, 0, 1) {
+		s.cache.SetHistoricalRates(date, map[string]decimal.Decimal{
+			"USD": decimal.NewFromInt(1),
+			"EUR": decimal.RequireFromString("0.8"),
+			"INR": decimal.NewFromInt(int64(80 + i)),
+		})
+	}
+
+	series, err := s.GetTimeseries("usd", []string{"INR"}, start, end)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(series.Rates) != 3 {
+		t.Fatalf("Expected 3 days, got %d", len(series.Rates))
+	}
+
+	for i, date := 0, start; !date.After(end); i, date = i+1, date.AddDate(0, 0, 1) {
+		rate := series.Rates[date.Format("2006-01-02")]["INR"]
+		if !rate.Equal(decimal.NewFromInt(int64(80 + i))) {
+			t.Errorf("INR on %s = %s, expected %d", date.Format("2006-01-02"), rate, 80+i)
+		}
+	}
+
+	if _, err := s.GetTimeseries("USD", nil, end, start); err == nil {
+		t.Error("Expected error when start_date is after end_date")
+	}
+}
+
+func TestGetRateTablesStopsAtFirstError(t *testing.T) {
+	var fetches atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer upstream.Close()
+
+	s := newTestRateFetcher(t, "USD,EUR")
+	s.apiClient = &APIClient{baseURL: upstream.URL, client: &http.Client{Timeout: time.Second}}
+	s.historicalConcurrency = 1
+
+	end := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	dates := []time.Time{end.AddDate(0, 0, -2), end.AddDate(0, 0, -1), end}
+
+	if _, err := s.getRateTables("USD", nil, dates); err == nil {
+		t.Fatal("Expected the failed fetch to fail the tables")
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("Expected the first failure to skip the other dates, got %d fetches", n)
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import "sync"
+
+// watchHistory is how many refreshed snapshots are kept for subscribers
+// that reconnect and need to catch up.
+const watchHistory = 24
+
+// watchers fans out each refreshed latest snapshot to subscribers. A slow
+// subscriber only ever holds the most recent snapshot: older ones it has
+// not received yet are dropped, and can be recovered with SnapshotsSince.
+type watchers struct {
+	subscribers map[chan *RateSnapshot]struct{}
+	history     []*RateSnapshot
+	mu          sync.Mutex
+}
+
+// WatchRates returns a channel that receives the latest snapshot after every
+// refresh, and a function that stops the subscription.
+func (s *RateFetcherService) WatchRates() (<-chan *RateSnapshot, func()) {
+	updates := make(chan *RateSnapshot, 1)
+
+	s.watchers.mu.Lock()
+	if s.watchers.subscribers == nil {
+		s.watchers.subscribers = make(map[chan *RateSnapshot]struct{})
+	}
+	s.watchers.subscribers[updates] = struct{}{}
+	s.watchers.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			s.watchers.mu.Lock()
+			defer s.watchers.mu.Unlock()
+
+			delete(s.watchers.subscribers, updates)
+			close(updates)
+		})
+	}
+
+	return updates, cancel
+}
+
+// SnapshotsSince returns the refreshed snapshots newer than version, oldest
+// first. It returns false when version is unknown, for example from before
+// a restart, or the snapshots after it are no longer retained, so the
+// caller cannot catch up without a gap.
+func (s *RateFetcherService) SnapshotsSince(version uint64) ([]*RateSnapshot, bool) {
+	s.watchers.mu.Lock()
+	defer s.watchers.mu.Unlock()
+
+	history := s.watchers.history
+	if len(history) == 0 || history[0].Version > version+1 || history[len(history)-1].Version < version {
+		return nil, false
+	}
+
+	var snapshots []*RateSnapshot
+	for _, snapshot := range history {
+		if snapshot.Version > version {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+
+	return snapshots, true
+}
+
+func (w *watchers) publish(snapshot *RateSnapshot) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.history = append(w.history, snapshot)
+	if len(w.history) > watchHistory {
+		w.history = w.history[len(w.history)-watchHistory:]
+	}
+
+	for updates := range w.subscribers {
+		select {
+		case <-updates:
+		default:
+		}
+		updates <- snapshot
+	}
+}
@@ -0,0 +1,55 @@
+package service
+
+import "testing"
+
+func TestWatchRatesKeepsLatestSnapshot(t *testing.T) {
+	s := &RateFetcherService{}
+
+	updates, cancel := s.WatchRates()
+
+	first := &RateSnapshot{Provider: "first"}
+	second := &RateSnapshot{Provider: "second"}
+	s.watchers.publish(first)
+	s.watchers.publish(second)
+
+	if got := <-updates; got != second {
+		t.Errorf("Expected only the latest snapshot, got %s", got.Provider)
+	}
+
+	cancel()
+	cancel()
+	if _, ok := <-updates; ok {
+		t.Error("Expected the channel to be closed after cancel")
+	}
+
+	s.watchers.publish(first)
+}
+
+func TestSnapshotsSince(t *testing.T) {
+	s := &RateFetcherService{}
+
+	if _, ok := s.SnapshotsSince(0); ok {
+		t.Error("Expected no history before the first refresh")
+	}
+
+	for version := uint64(1); version <= watchHistory+5; version++ {
+		s.watchers.publish(&RateSnapshot{Version: version})
+	}
+
+	snapshots, ok := s.SnapshotsSince(watchHistory + 2)
+	if !ok || len(snapshots) != 3 || snapshots[0].Version != watchHistory+3 {
+		t.Errorf("Expected the 3 newest snapshots, got %d (ok=%v)", len(snapshots), ok)
+	}
+
+	if snapshots, ok := s.SnapshotsSince(watchHistory + 5); !ok || len(snapshots) != 0 {
+		t.Error("Expected an up-to-date client to need nothing")
+	}
+
+	if _, ok := s.SnapshotsSince(2); ok {
+		t.Error("Expected a gap once version 3 is no longer retained")
+	}
+
+	if _, ok := s.SnapshotsSince(1000); ok {
+		t.Error("Expected an unknown future version to be rejected")
+	}
+}
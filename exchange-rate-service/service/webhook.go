@@ -0,0 +1,232 @@
+package service
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"os"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultWebhookRetries = 3
+	defaultWebhookTimeout = 10 * time.Second
+	webhookBackoff        = time.Second
+
+	// deadLetterHistory is how many failed deliveries are kept in memory
+	// for the API; the dead-letter file keeps all of them.
+	deadLetterHistory = 100
+)
+
+// SignatureHeader carries the HMAC-SHA256 of a webhook payload, and
+// TimestampHeader the Unix time it was signed at.
+const (
+	SignatureHeader = "X-Alert-Signature"
+	TimestampHeader = "X-Alert-Timestamp"
+	DeliveryHeader  = "X-Alert-Delivery"
+)
+
+// SignWebhook returns the signature of body sent at timestamp: "sha256="
+// followed by the hex HMAC-SHA256 of "<timestamp>.<body>" keyed by secret.
+// Receivers recompute it to verify a delivery and reject old timestamps to
+// prevent replays.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// newWebhookClient returns the client deliveries are posted with. Unless
+// allowPrivate is set, it refuses to connect to loopback, private,
+// link-local and other non-public addresses. The check runs on the address
+// being dialed, after DNS resolution and on every redirect, so a rule
+// cannot reach inside the network through a public name. Proxies are not
+// used, since the proxy address is what would be checked.
+func newWebhookClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = refusePrivateAddress
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// refusePrivateAddress is a net.Dialer Control function that only allows
+// public unicast addresses.
+func refusePrivateAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() || sharedAddressSpace.Contains(ip) {
+		return fmt.Errorf("webhook address %s is not public", ip)
+	}
+
+	return nil
+}
+
+// sharedAddressSpace is the carrier-grade NAT range, which netip does not
+// count as private.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// webhookDelivery is one payload for one rule's webhook.
+type webhookDelivery struct {
+	ID      string
+	RuleID  string
+	URL     string
+	Secret  string
+	Payload []byte
+}
+
+// DeadLetter records a delivery that failed after every retry.
+type DeadLetter struct {
+	DeliveryID string          `json:"delivery_id"`
+	RuleID     string          `json:"rule_id"`
+	URL        string          `json:"url"`
+	Attempts   int             `json:"attempts"`
+	Error      string          `json:"error"`
+	FailedAt   time.Time       `json:"failed_at"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// webhookSender posts signed deliveries, retrying failures with exponential
+// backoff. Deliveries that still fail are appended to the dead-letter file.
+type webhookSender struct {
+	client  *http.Client
+	retries int
+	backoff time.Duration
+
+	deadLetterPath string
+	deadLetters    []DeadLetter
+	mu             sync.Mutex
+}
+
+// send delivers d, returning the number of attempts made and the last
+// error. Client errors other than 408 and 429 are not retried.
+func (w *webhookSender) send(d webhookDelivery) (int, error) {
+	var err error
+	backoff := w.backoff
+
+	for attempt := 1; ; attempt++ {
+		var retry bool
+		retry, err = w.post(d)
+		if err == nil {
+			return attempt, nil
+		}
+
+		if !retry || attempt > w.retries {
+			return attempt, err
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (w *webhookSender) post(d webhookDelivery) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return false, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(DeliveryHeader, d.ID)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, SignWebhook(d.Secret, timestamp, d.Payload))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+}
+
+// deliver sends d and dead-letters it on failure.
+func (w *webhookSender) deliver(d webhookDelivery) {
+	attempts, err := w.send(d)
+	if err == nil {
+		return
+	}
+
+	w.deadLetter(DeadLetter{
+		DeliveryID: d.ID,
+		RuleID:     d.RuleID,
+		URL:        d.URL,
+		Attempts:   attempts,
+		Error:      err.Error(),
+		FailedAt:   time.Now().UTC(),
+		Payload:    d.Payload,
+	})
+}
+
+func (w *webhookSender) deadLetter(letter DeadLetter) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	log.Printf("Alert delivery %s to %s failed after %d attempts: %s", letter.DeliveryID, letter.URL, letter.Attempts, letter.Error)
+
+	w.deadLetters = append(w.deadLetters, letter)
+	if len(w.deadLetters) > deadLetterHistory {
+		w.deadLetters = w.deadLetters[len(w.deadLetters)-deadLetterHistory:]
+	}
+
+	if w.deadLetterPath == "" {
+		return
+	}
+
+	line, err := json.Marshal(letter)
+	if err != nil {
+		return
+	}
+
+	file, err := os.OpenFile(w.deadLetterPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		log.Printf("Failed to write dead letter: %v", err)
+		return
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		log.Printf("Failed to write dead letter: %v", err)
+	}
+}
+
+// DeadLetters returns the most recent failed deliveries, oldest first.
+func (w *webhookSender) DeadLetters() []DeadLetter {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return append([]DeadLetter(nil), w.deadLetters...)
+}
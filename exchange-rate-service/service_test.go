@@ -26,9 +26,11 @@ func setupTestServer(t *testing.T) *gin.Engine {
 
 	rateFetcher := service.NewRateFetcherService()
 	convertHandler := handler.NewConvertHandler(rateFetcher)
+	ratesHandler := handler.NewRatesHandler(rateFetcher)
 
 	router := gin.New()
 	router.GET("/convert", convertHandler.HandleConvert)
+	router.GET("/rates", ratesHandler.HandleRates)
 
 	return router
 }
@@ -56,10 +58,10 @@ func TestIntegration_BasicConversion(t *testing.T) {
 		t.Fatalf("Failed to parse response: %v", err)
 	}
 
-	// Handler only returns {"amount": "result_value"}
-	amountStr, ok := result["amount"].(string)
+	// Converted value is returned under "result"; "amount" echoes the input
+	amountStr, ok := result["result"].(string)
 	if !ok {
-		t.Errorf("Expected amount to be string, got %T", result["amount"])
+		t.Errorf("Expected amount to be string, got %T", result["result"])
 	}
 
 	t.Logf("✓ Converted 100 USD to INR: %s", amountStr)
@@ -80,9 +82,9 @@ func TestIntegration_DecimalAmount(t *testing.T) {
 	var result map[string]interface{}
 	json.Unmarshal(resp.Body.Bytes(), &result)
 
-	amountStr, ok := result["amount"].(string)
+	amountStr, ok := result["result"].(string)
 	if !ok {
-		t.Errorf("Expected amount to be string, got %T", result["amount"])
+		t.Errorf("Expected amount to be string, got %T", result["result"])
 	}
 
 	t.Logf("✓ Converted 123.45 USD to EUR: %s", amountStr)
@@ -114,8 +116,8 @@ func TestIntegration_AllCurrencyPairs(t *testing.T) {
 				var result map[string]interface{}
 				json.Unmarshal(resp.Body.Bytes(), &result)
 
-				if _, ok := result["amount"].(string); !ok {
-					t.Errorf("Expected string amount, got %T", result["amount"])
+				if _, ok := result["result"].(string); !ok {
+					t.Errorf("Expected string amount, got %T", result["result"])
 				}
 			})
 		}
@@ -142,9 +144,9 @@ func TestIntegration_HistoricalConversion(t *testing.T) {
 	var result map[string]interface{}
 	json.Unmarshal(resp.Body.Bytes(), &result)
 
-	amountStr, ok := result["amount"].(string)
+	amountStr, ok := result["result"].(string)
 	if !ok {
-		t.Fatalf("Expected string amount, got %T", result["amount"])
+		t.Fatalf("Expected string amount, got %T", result["result"])
 	}
 
 	t.Logf("✓ Historical conversion: 100 EUR to GBP on %s = %s", date, amountStr)
@@ -192,9 +194,9 @@ func TestIntegration_BTCSupported(t *testing.T) {
 	var result map[string]interface{}
 	json.Unmarshal(resp.Body.Bytes(), &result)
 
-	amountStr, ok := result["amount"].(string)
+	amountStr, ok := result["result"].(string)
 	if !ok {
-		t.Errorf("Expected string amount, got %T", result["amount"])
+		t.Errorf("Expected string amount, got %T", result["result"])
 	}
 
 	t.Logf("✓ BTC supported: 1 BTC to USD = %s", amountStr)
@@ -331,11 +333,64 @@ func TestIntegration_ResultConsistency(t *testing.T) {
 	json.Unmarshal(resp1.Body.Bytes(), &result1)
 	json.Unmarshal(resp2.Body.Bytes(), &result2)
 
-	if result1["amount"] != result2["amount"] {
+	if result1["result"] != result2["result"] {
 		t.Error("Cache should return identical results")
 	}
 
-	t.Logf("✓ Results consistent: %s", result1["amount"])
+	t.Logf("✓ Results consistent: %s", result1["result"])
+}
+
+func TestIntegration_ConversionDetails(t *testing.T) {
+	router := setupTestServer(t)
+
+	req := httptest.NewRequest("GET", "/convert?from=USD&to=INR&amount=100", nil)
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d. Body: %s", resp.Code, resp.Body.String())
+	}
+
+	var result map[string]interface{}
+	json.Unmarshal(resp.Body.Bytes(), &result)
+
+	for _, field := range []string{"from", "to", "amount", "result", "rate", "inverse_rate", "timestamp", "provider", "cache_age_seconds", "stale"} {
+		if _, ok := result[field]; !ok {
+			t.Errorf("Expected field %q in response", field)
+		}
+	}
+
+	if result["from"] != "USD" || result["to"] != "INR" || result["amount"] != "100.00" {
+		t.Errorf("Unexpected echo of the request: %v", result)
+	}
+
+	t.Log("✓ Conversion details returned")
+}
+
+func TestIntegration_LegacyResponse(t *testing.T) {
+	router := setupTestServer(t)
+
+	req := httptest.NewRequest("GET", "/convert?from=USD&to=INR&amount=100&legacy=true", nil)
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d. Body: %s", resp.Code, resp.Body.String())
+	}
+
+	var result map[string]interface{}
+	json.Unmarshal(resp.Body.Bytes(), &result)
+
+	if len(result) != 1 {
+		t.Errorf("Expected only the amount field, got %v", result)
+	}
+	if _, ok := result["amount"].(string); !ok {
+		t.Errorf("Expected string amount, got %T", result["amount"])
+	}
+
+	t.Log("✓ Legacy response shape preserved")
 }
 
 func TestIntegration_SameCurrencyConversion(t *testing.T) {
@@ -353,10 +408,38 @@ func TestIntegration_SameCurrencyConversion(t *testing.T) {
 	var result map[string]interface{}
 	json.Unmarshal(resp.Body.Bytes(), &result)
 
-	amountStr := result["amount"].(string)
+	amountStr := result["result"].(string)
 	if amountStr != "100" {
 		t.Errorf("Same currency conversion should return exact amount, got %s", amountStr)
 	}
 
 	t.Log("✓ Same currency conversion works correctly")
 }
+
+func TestIntegration_RateTable(t *testing.T) {
+	router := setupTestServer(t)
+
+	req := httptest.NewRequest("GET", "/rates?base=EUR&symbols=USD,INR", nil)
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d. Body: %s", resp.Code, resp.Body.String())
+	}
+
+	var result struct {
+		Base     string            `json:"base"`
+		Provider string            `json:"provider"`
+		Rates    map[string]string `json:"rates"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+
+	if result.Base != "EUR" || len(result.Rates) != 2 {
+		t.Errorf("Expected EUR table with USD and INR, got %+v", result)
+	}
+
+	t.Logf("✓ Rate table re-based to EUR: %v", result.Rates)
+}